@@ -0,0 +1,182 @@
+package tablo
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+const (
+	alignSpecSeparator = ":"
+	alignAllColumns    = "*"
+)
+
+var alignNames = map[string]text.Align{
+	"left":   text.AlignLeft,
+	"l":      text.AlignLeft,
+	"center": text.AlignCenter,
+	"c":      text.AlignCenter,
+	"right":  text.AlignRight,
+	"r":      text.AlignRight,
+}
+
+// columnAlign sets the alignment of a column given by header name or, when
+// index is not negative, by zero-based output column index. The * column
+// applies to every column.
+type columnAlign struct {
+	column string
+	index  int
+	align  text.Align
+}
+
+// parseColumnAligns parses a comma separated list of COLUMN:left|center|right
+// entries where COLUMN is a header name, a 1-based column index or *.
+func parseColumnAligns(spec string) ([]columnAlign, error) {
+	var aligns []columnAlign
+
+	for item := range strings.SplitSeq(spec, ",") {
+		item = strings.TrimSpace(item)
+		idx := strings.LastIndex(item, alignSpecSeparator)
+		if idx <= 0 {
+			return nil, fmt.Errorf("%w, align entry %q must be COLUMN:left|center|right", ErrInvalidValue, item)
+		}
+
+		column, name := item[:idx], item[idx+1:]
+		align, ok := alignNames[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w, align entry %q has unknown alignment %q", ErrInvalidValue, item, name)
+		}
+
+		entry := columnAlign{column: column, index: -1, align: align}
+		if n, err := strconv.Atoi(column); err == nil {
+			if n < 1 {
+				return nil, fmt.Errorf("%w, align column index %d must be greater than zero", ErrInvalidValue, n)
+			}
+			entry.index = n - 1
+		}
+		aligns = append(aligns, entry)
+	}
+
+	return aligns, nil
+}
+
+// isNumericCell reports whether a cell holds a number, a percentage or a
+// human readable size such as 12.7MB.
+func isNumericCell(value string) bool {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "%")
+	if !strings.ContainsAny(value, "0123456789") {
+		return false
+	}
+	if _, ok := parseNumber(value); ok {
+		return true
+	}
+	_, ok := parseHumanSize(value)
+
+	return ok
+}
+
+// numericColumns marks the columns whose non-empty cells are all numeric.
+// With allowTitle a non-numeric first row cell is taken as a column title
+// of an undetected header.
+func numericColumns(rows [][]string, allowTitle bool) []bool {
+	var columns int
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	numeric := make([]bool, columns)
+	for i := range numeric {
+		seen := false
+		numeric[i] = true
+		for j, row := range rows {
+			value := fieldAt(row, i)
+			if strings.TrimSpace(value) == "" {
+				continue
+			}
+			if !isNumericCell(value) && j == 0 && allowTitle {
+				continue
+			}
+			seen = true
+			if !isNumericCell(value) {
+				numeric[i] = false
+				break
+			}
+		}
+		numeric[i] = numeric[i] && seen
+	}
+
+	return numeric
+}
+
+// columnAlignments returns the alignment of every output column: numeric
+// columns are right aligned unless -align says otherwise. Without headers
+// the first row may hold column titles.
+func (t *Tablo) columnAlignments(headers []string, rows [][]string) ([]text.Align, error) {
+	numeric := numericColumns(rows, headers == nil && len(rows) > 1)
+	aligns := make([]text.Align, max(len(headers), len(numeric)))
+	for i := range aligns {
+		if i < len(numeric) && numeric[i] {
+			aligns[i] = text.AlignRight
+		}
+	}
+
+	for _, entry := range t.Align {
+		if entry.column == alignAllColumns {
+			for i := range aligns {
+				aligns[i] = entry.align
+			}
+
+			continue
+		}
+
+		idx := entry.index
+		if idx < 0 {
+			idx = slices.IndexFunc(headers, func(header string) bool {
+				return strings.EqualFold(header, entry.column)
+			})
+		}
+		if idx < 0 || idx >= len(aligns) {
+			return nil, fmt.Errorf("%w, align column %q not found", ErrInvalidValue, entry.column)
+		}
+		aligns[idx] = entry.align
+	}
+
+	return aligns, nil
+}
+
+// columnConfigs turns column alignments, width limits and color rules into
+// go-pretty column configs, headers and footers follow the alignment of their
+// column.
+func (t *Tablo) columnConfigs(headers []string, rows [][]string, widthMax []int) ([]table.ColumnConfig, error) {
+	aligns, err := t.columnAlignments(headers, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	colors, err := t.columnColors(headers, len(aligns))
+	if err != nil {
+		return nil, err
+	}
+
+	var configs []table.ColumnConfig
+	for i := range max(len(aligns), len(widthMax), len(colors)) {
+		config := table.ColumnConfig{Number: i + 1}
+		if i < len(aligns) {
+			config.Align, config.AlignHeader, config.AlignFooter = aligns[i], aligns[i], aligns[i]
+		}
+		if i < len(widthMax) && widthMax[i] > 0 {
+			config.WidthMax, config.WidthMaxEnforcer = widthMax[i], t.widthEnforcer()
+		}
+		config.Transformer = colors.transformer(i)
+		if config.Align == text.AlignDefault && config.WidthMax == 0 && config.Transformer == nil {
+			continue
+		}
+		configs = append(configs, config)
+	}
+
+	return configs, nil
+}
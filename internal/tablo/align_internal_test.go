@@ -0,0 +1,109 @@
+package tablo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseColumnAligns(t *testing.T) {
+	aligns, err := parseColumnAligns("SIZE:right, image id:C,2:left,*:r")
+
+	require.NoError(t, err)
+	assert.Equal(t, []columnAlign{
+		{column: "SIZE", index: -1, align: text.AlignRight},
+		{column: "image id", index: -1, align: text.AlignCenter},
+		{column: "2", index: 1, align: text.AlignLeft},
+		{column: "*", index: -1, align: text.AlignRight},
+	}, aligns)
+
+	for _, spec := range []string{"SIZE", ":left", "SIZE:middle", "0:left", "SIZE:left,"} {
+		_, err = parseColumnAligns(spec)
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestIsNumericCell(t *testing.T) {
+	for _, value := range []string{"42", "-1.5", "1e3", "12.7MB", "4.0K", "1GiB", "40%", " 7 "} {
+		assert.True(t, isNumericCell(value), value)
+	}
+	for _, value := range []string{"", "abc", "NaN", "Inf", "%", "2 weeks ago", "v1.2.3", "12 apples"} {
+		assert.False(t, isNumericCell(value), value)
+	}
+}
+
+func TestNumericColumns(t *testing.T) {
+	rows := [][]string{
+		{"web", "10", "", "1"},
+		{"db", "2.5MB", "", "x"},
+		{"cache", ""},
+	}
+
+	assert.Equal(t, []bool{false, true, false, false}, numericColumns(rows, false))
+
+	rows = [][]string{{"Name", "Size"}, {"a", "50G"}, {"b", "1.2G"}}
+
+	assert.Equal(t, []bool{false, false}, numericColumns(rows, false))
+	assert.Equal(t, []bool{false, true}, numericColumns(rows, true))
+}
+
+func TestTablo_ColumnConfigs(t *testing.T) {
+	aligns, err := parseColumnAligns("name:center,3:left")
+	require.NoError(t, err)
+
+	tbl := &Tablo{Align: aligns}
+	configs, err := tbl.columnConfigs(
+		[]string{"name", "size", "count"},
+		[][]string{{"web", "10MB", "3"}},
+		nil,
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []table.ColumnConfig{
+		{Number: 1, Align: text.AlignCenter, AlignHeader: text.AlignCenter, AlignFooter: text.AlignCenter},
+		{Number: 2, Align: text.AlignRight, AlignHeader: text.AlignRight, AlignFooter: text.AlignRight},
+		{Number: 3, Align: text.AlignLeft, AlignHeader: text.AlignLeft, AlignFooter: text.AlignLeft},
+	}, configs)
+}
+
+func TestTablo_ColumnConfigs_AllColumns(t *testing.T) {
+	aligns, err := parseColumnAligns("*:left,name:right")
+	require.NoError(t, err)
+
+	tbl := &Tablo{Align: aligns}
+	configs, err := tbl.columnConfigs([]string{"name", "size"}, [][]string{{"web", "10"}}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []table.ColumnConfig{
+		{Number: 1, Align: text.AlignRight, AlignHeader: text.AlignRight, AlignFooter: text.AlignRight},
+		{Number: 2, Align: text.AlignLeft, AlignHeader: text.AlignLeft, AlignFooter: text.AlignLeft},
+	}, configs)
+}
+
+func TestTablo_ColumnConfigs_UnknownColumn(t *testing.T) {
+	for _, spec := range []string{"age:left", "3:left"} {
+		aligns, err := parseColumnAligns(spec)
+		require.NoError(t, err)
+
+		tbl := &Tablo{Align: aligns}
+		_, err = tbl.columnConfigs([]string{"name", "size"}, [][]string{{"web", "10"}}, nil)
+
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestTablo_RenderFormat_Markdown_AlignsNumericColumns(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		FieldDelimiter: '|',
+		Format:         FormatMarkdown,
+	}
+
+	require.NoError(t, tbl.renderFormat(tbl.buildJSONDataset(tbl.splitRecords([]string{"name|size", "web|10MB"}))))
+	assert.Equal(t, "| name | size |\n| --- | ---:|\n| web | 10MB |\n", out.String())
+}
@@ -0,0 +1,231 @@
+package tablo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// ColorMode controls when -color rules paint cells.
+type ColorMode string
+
+// color modes.
+const (
+	ColorAuto   ColorMode = "auto"
+	ColorAlways ColorMode = "always"
+	ColorNever  ColorMode = "never"
+)
+
+const (
+	colorRuleSeparator = "="
+	colorSeparator     = "+"
+	colorHiPrefix      = "hi-"
+	colorBgPrefix      = "bg-"
+	noColorEnv         = "NO_COLOR"
+)
+
+var (
+	colorModes = []ColorMode{ColorAuto, ColorAlways, ColorNever}
+	colorNames = map[string]text.Color{
+		"black":     text.FgBlack,
+		"red":       text.FgRed,
+		"green":     text.FgGreen,
+		"yellow":    text.FgYellow,
+		"blue":      text.FgBlue,
+		"magenta":   text.FgMagenta,
+		"cyan":      text.FgCyan,
+		"white":     text.FgWhite,
+		"bold":      text.Bold,
+		"faint":     text.Faint,
+		"italic":    text.Italic,
+		"underline": text.Underline,
+	}
+)
+
+// IsTerminal reports whether w is a terminal, -color=auto only paints
+// cells on a terminal.
+var IsTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorRule paints the cells of a column given by header name or, when
+// index is not negative, by zero-based output column index.
+type colorRule struct {
+	column string
+	index  int
+	match  *whereComparison
+	colors text.Colors
+}
+
+// columnColors holds the color rules of every output column.
+type columnColors [][]colorRule
+
+// parseColor parses a comma separated list of COLUMN OP VALUE=COLOR rules,
+// where OP is one of the -where operators, and an optional color mode.
+func parseColor(spec string) (ColorMode, []colorRule, error) {
+	mode := ColorAuto
+	var rules []colorRule
+
+	for item := range strings.SplitSeq(spec, ",") {
+		item = strings.TrimSpace(item)
+		if slices.Contains(colorModes, ColorMode(strings.ToLower(item))) {
+			mode = ColorMode(strings.ToLower(item))
+			continue
+		}
+
+		rule, err := parseColorRule(item)
+		if err != nil {
+			return "", nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return mode, rules, nil
+}
+
+func colorModeNames() []string {
+	names := make([]string, 0, len(colorModes))
+	for _, mode := range colorModes {
+		names = append(names, string(mode))
+	}
+
+	return names
+}
+
+func parseColorRule(item string) (colorRule, error) {
+	idx := strings.LastIndex(item, colorRuleSeparator)
+	if idx <= 0 {
+		return colorRule{}, fmt.Errorf("%w, color rule %q must be COLUMN OP VALUE=COLOR", ErrInvalidValue, item)
+	}
+
+	colors, err := parseColors(item[idx+1:])
+	if err != nil {
+		return colorRule{}, err
+	}
+
+	node, err := parseWhere(item[:idx])
+	if err != nil {
+		return colorRule{}, err
+	}
+	comparison, ok := node.(*whereComparison)
+	if !ok {
+		return colorRule{}, fmt.Errorf("%w, color rule %q must compare a single column", ErrInvalidValue, item)
+	}
+
+	rule := colorRule{column: comparison.column, index: comparison.index, match: comparison, colors: colors}
+	// the comparison is evaluated against the cell alone.
+	comparison.index = 0
+
+	return rule, nil
+}
+
+// parseColors parses color names such as red, hi-red, bg-blue, bg-hi-black
+// or bold, combined with +.
+func parseColors(spec string) (text.Colors, error) {
+	var colors text.Colors
+
+	for name := range strings.SplitSeq(strings.ToLower(strings.TrimSpace(spec)), colorSeparator) {
+		base, offset := name, text.Color(0)
+		if strings.HasPrefix(base, colorBgPrefix) {
+			base, offset = strings.TrimPrefix(base, colorBgPrefix), text.BgBlack-text.FgBlack
+		}
+		if strings.HasPrefix(base, colorHiPrefix) {
+			base, offset = strings.TrimPrefix(base, colorHiPrefix), offset+text.FgHiBlack-text.FgBlack
+		}
+
+		color, ok := colorNames[base]
+		if !ok || (offset != 0 && color < text.FgBlack) {
+			return nil, fmt.Errorf("%w, unknown color %q", ErrInvalidValue, name)
+		}
+		colors = append(colors, color+offset)
+	}
+
+	return colors, nil
+}
+
+// colorEnabled reports whether table cells are painted by -color rules.
+func (t *Tablo) colorEnabled() bool {
+	return len(t.ColorRules) > 0 && t.colorOutput()
+}
+
+// colorOutput reports whether table output may be colored at all. auto
+// colors when the output is a terminal and NO_COLOR is not set.
+func (t *Tablo) colorOutput() bool {
+	if t.JSONOutput || (t.Format != "" && t.Format != FormatTable) {
+		return false
+	}
+
+	switch t.ColorMode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		return t.env(noColorEnv) == "" && IsTerminal(t.Output)
+	}
+}
+
+// columnColors resolves the color rules against the output headers, nil
+// when nothing is painted.
+func (t *Tablo) columnColors(headers []string, columns int) (columnColors, error) {
+	if !t.colorEnabled() {
+		return nil, nil
+	}
+
+	colors := make(columnColors, max(len(headers), columns))
+	for _, rule := range t.ColorRules {
+		idx := rule.index
+		if idx < 0 {
+			idx = slices.IndexFunc(headers, func(header string) bool {
+				return strings.EqualFold(header, rule.column)
+			})
+		}
+		if idx < 0 || idx >= len(colors) {
+			return nil, fmt.Errorf("%w, color column %q not found", ErrInvalidValue, rule.column)
+		}
+		colors[idx] = append(colors[idx], rule)
+	}
+
+	return colors, nil
+}
+
+// paint colors a cell with the first matching rule of its column. value is
+// matched, cell is what gets rendered.
+func (c columnColors) paint(column int, value, cell string) string {
+	if column >= len(c) {
+		return cell
+	}
+	for _, rule := range c[column] {
+		if rule.match.eval([]string{value}) {
+			return text.Escape(cell, rule.colors.EscapeSeq())
+		}
+	}
+
+	return cell
+}
+
+// transformer paints the cells of a column in go-pretty tables.
+func (c columnColors) transformer(column int) text.Transformer {
+	if column >= len(c) || len(c[column]) == 0 {
+		return nil
+	}
+
+	return func(val any) string {
+		value := fmt.Sprint(val)
+
+		return c.paint(column, value, value)
+	}
+}
@@ -0,0 +1,112 @@
+package tablo
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseColor(t *testing.T) {
+	mode, rules, err := parseColor("STATUS~Exited=red, CPU>80=hi-red+bold,never")
+
+	require.NoError(t, err)
+	assert.Equal(t, ColorNever, mode)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "STATUS", rules[0].column)
+	assert.Equal(t, -1, rules[0].index)
+	assert.Equal(t, text.Colors{text.FgRed}, rules[0].colors)
+	assert.Equal(t, "CPU", rules[1].column)
+	assert.Equal(t, text.Colors{text.FgHiRed, text.Bold}, rules[1].colors)
+
+	mode, rules, err = parseColor("always")
+
+	require.NoError(t, err)
+	assert.Equal(t, ColorAlways, mode)
+	assert.Empty(t, rules)
+
+	for _, spec := range []string{
+		"", "STATUS", "=red", "STATUS~Up=pink", "STATUS~Up=bg-bold",
+		"STATUS~Up and CPU>1=red", "STATUS ~ =red",
+	} {
+		_, _, err = parseColor(spec)
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestParseColors(t *testing.T) {
+	colors, err := parseColors("bg-blue+hi-green+underline+bg-hi-black")
+
+	require.NoError(t, err)
+	assert.Equal(t, text.Colors{text.BgBlue, text.FgHiGreen, text.Underline, text.BgHiBlack}, colors)
+
+	_, err = parseColors("hi-bg-black")
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestTablo_ColorEnabled(t *testing.T) {
+	originalIsTerminal := IsTerminal
+	t.Cleanup(func() { IsTerminal = originalIsTerminal })
+
+	terminal := true
+	IsTerminal = func(io.Writer) bool { return terminal }
+	t.Setenv(noColorEnv, "")
+
+	_, rules, err := parseColor("STATUS~Up=green")
+	require.NoError(t, err)
+
+	tbl := &Tablo{ColorRules: rules}
+	assert.True(t, tbl.colorEnabled())
+
+	terminal = false
+	assert.False(t, tbl.colorEnabled())
+
+	tbl.ColorMode = ColorAlways
+	assert.True(t, tbl.colorEnabled())
+
+	tbl.Format = FormatMarkdown
+	assert.False(t, tbl.colorEnabled())
+
+	tbl.Format = ""
+	tbl.JSONOutput = true
+	assert.False(t, tbl.colorEnabled())
+
+	tbl.JSONOutput = false
+	tbl.ColorMode = ColorAuto
+	terminal = true
+	t.Setenv(noColorEnv, "1")
+	assert.False(t, tbl.colorEnabled())
+
+	tbl.ColorRules = nil
+	tbl.ColorMode = ColorAlways
+	assert.False(t, tbl.colorEnabled())
+}
+
+func TestTablo_ColumnColors(t *testing.T) {
+	_, rules, err := parseColor("always,status~Up=green,2>80=red,2>50=yellow")
+	require.NoError(t, err)
+
+	tbl := &Tablo{ColorMode: ColorAlways, ColorRules: rules}
+	colors, err := tbl.columnColors([]string{"STATUS", "CPU"}, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, "\x1b[32mUp 2 hours\x1b[0m", colors.paint(0, "Up 2 hours", "Up 2 hours"))
+	assert.Equal(t, "Exited", colors.paint(0, "Exited", "Exited"))
+	assert.Equal(t, "\x1b[31m 90\x1b[0m", colors.paint(1, "90", " 90"))
+	assert.Equal(t, "\x1b[33m60\x1b[0m", colors.transformer(1)("60"))
+	assert.Nil(t, colors.transformer(2))
+
+	tbl.ColorRules = append(tbl.ColorRules, colorRule{column: "missing", index: -1})
+	_, err = tbl.columnColors([]string{"STATUS", "CPU"}, 2)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	var out bytes.Buffer
+	tbl = &Tablo{Output: nopWriteCloser{&out}, ColorRules: rules}
+	colors, err = tbl.columnColors([]string{"STATUS", "CPU"}, 2)
+
+	require.NoError(t, err)
+	assert.Nil(t, colors)
+}
@@ -15,14 +15,28 @@ import (
 const (
 	shortBashCompletionFlag = "-bash-completion"
 	bashCompletionFlag      = "--bash-completion"
+	shortZshCompletionFlag  = "-zsh-completion"
+	zshCompletionFlag       = "--zsh-completion"
+	shortFishCompletionFlag = "-fish-completion"
+	fishCompletionFlag      = "--fish-completion"
 	completeFlag            = "--complete"
 	completionByteCap       = 64 * 1024
+
+	// completionShellEnv is set by the zsh and fish scripts, flags are then
+	// printed with a tab and their description and completionFilesMarker
+	// asks the shell to complete paths.
+	completionShellEnv    = "COMP_SHELL"
+	completionFilesMarker = ":files"
 )
 
 var (
 	completionBooleanFlags = map[string]struct{}{
 		shortBashCompletionFlag: {},
 		bashCompletionFlag:      {},
+		shortZshCompletionFlag:  {},
+		zshCompletionFlag:       {},
+		shortFishCompletionFlag: {},
+		fishCompletionFlag:      {},
 		"-h":                    {},
 		"-help":                 {},
 		"--help":                {},
@@ -40,24 +54,103 @@ var (
 		"-j":                    {},
 		"-json":                 {},
 		"--json":                {},
+		"-rs":                   {},
+		"-raw-split":            {},
+		"--raw-split":           {},
+		"-st":                   {},
+		"-stream":               {},
+		"--stream":              {},
+		"-vw":                   {},
+		"-view":                 {},
+		"--view":                {},
+		"-sc":                   {},
+		"-show-config":          {},
+		"--show-config":         {},
 	}
 	completionValueFlags = map[string]struct{}{
-		"-f":                     {},
-		"-field-delimiter-char":  {},
-		"--field-delimiter-char": {},
-		"-l":                     {},
-		"-line-delimiter-char":   {},
-		"--line-delimiter-char":  {},
-		"-fi":                    {},
-		"-filter-indexes":        {},
-		"--filter-indexes":       {},
-		"-o":                     {},
-		"-output":                {},
-		"--output":               {},
+		"-f":                      {},
+		"-field-delimiter-char":   {},
+		"--field-delimiter-char":  {},
+		"-f-regex":                {},
+		"-field-delimiter-regex":  {},
+		"--field-delimiter-regex": {},
+		"-l":                      {},
+		"-line-delimiter-char":    {},
+		"--line-delimiter-char":   {},
+		"-fi":                     {},
+		"-filter-indexes":         {},
+		"--filter-indexes":        {},
+		"-s":                      {},
+		"-sort":                   {},
+		"--sort":                  {},
+		"-w":                      {},
+		"-where":                  {},
+		"--where":                 {},
+		"-sw":                     {},
+		"-stream-window":          {},
+		"--stream-window":         {},
+		"-fmt":                    {},
+		"-format":                 {},
+		"--format":                {},
+		"-sy":                     {},
+		"-style":                  {},
+		"--style":                 {},
+		"-th":                     {},
+		"-theme":                  {},
+		"--theme":                 {},
+		"-if":                     {},
+		"-input-format":           {},
+		"--input-format":          {},
+		"-fw":                     {},
+		"-widths":                 {},
+		"--widths":                {},
+		"-c":                      {},
+		"-color":                  {},
+		"--color":                 {},
+		"-a":                      {},
+		"-align":                  {},
+		"--align":                 {},
+		"-mw":                     {},
+		"-max-width":              {},
+		"--max-width":             {},
+		"-wm":                     {},
+		"-width-mode":             {},
+		"--width-mode":            {},
+		"-ft":                     {},
+		"-footer":                 {},
+		"--footer":                {},
+		"-gb":                     {},
+		"-group-by":               {},
+		"--group-by":              {},
+		"-ag":                     {},
+		"-agg":                    {},
+		"--agg":                   {},
+		"-rn":                     {},
+		"-rename":                 {},
+		"--rename":                {},
+		"-ac":                     {},
+		"-add-column":             {},
+		"--add-column":            {},
+		"-jt":                     {},
+		"-json-types":             {},
+		"--json-types":            {},
+		"-js":                     {},
+		"-json-schema":            {},
+		"--json-schema":           {},
+		"-p":                      {},
+		"-profile":                {},
+		"--profile":               {},
+		"-o":                      {},
+		"-output":                 {},
+		"--output":                {},
 	}
 	completionAllFlags = []string{
 		shortBashCompletionFlag,
 		bashCompletionFlag,
+		shortZshCompletionFlag,
+		zshCompletionFlag,
+		shortFishCompletionFlag,
+		fishCompletionFlag,
 		"-h",
 		"-help",
 		"--help",
@@ -66,6 +159,9 @@ var (
 		"-f",
 		"-field-delimiter-char",
 		"--field-delimiter-char",
+		"-f-regex",
+		"-field-delimiter-regex",
+		"--field-delimiter-regex",
 		"-l",
 		"-line-delimiter-char",
 		"--line-delimiter-char",
@@ -84,15 +180,151 @@ var (
 		"-j",
 		"-json",
 		"--json",
+		"-fmt",
+		"-format",
+		"--format",
+		"-sy",
+		"-style",
+		"--style",
+		"-th",
+		"-theme",
+		"--theme",
+		"-if",
+		"-input-format",
+		"--input-format",
+		"-fw",
+		"-widths",
+		"--widths",
+		"-c",
+		"-color",
+		"--color",
+		"-a",
+		"-align",
+		"--align",
+		"-mw",
+		"-max-width",
+		"--max-width",
+		"-wm",
+		"-width-mode",
+		"--width-mode",
+		"-ft",
+		"-footer",
+		"--footer",
+		"-gb",
+		"-group-by",
+		"--group-by",
+		"-ag",
+		"-agg",
+		"--agg",
+		"-rn",
+		"-rename",
+		"--rename",
+		"-ac",
+		"-add-column",
+		"--add-column",
+		"-jt",
+		"-json-types",
+		"--json-types",
+		"-js",
+		"-json-schema",
+		"--json-schema",
+		"-p",
+		"-profile",
+		"--profile",
+		"-sc",
+		"-show-config",
+		"--show-config",
+		"-rs",
+		"-raw-split",
+		"--raw-split",
+		"-s",
+		"-sort",
+		"--sort",
+		"-w",
+		"-where",
+		"--where",
+		"-st",
+		"-stream",
+		"--stream",
+		"-sw",
+		"-stream-window",
+		"--stream-window",
+		"-vw",
+		"-view",
+		"--view",
 		"-o",
 		"-output",
 		"--output",
 	}
 )
 
+// completionFlagHelp describes the flags for zsh and fish with the help
+// texts of the usage output.
+var completionFlagHelp = []struct {
+	short string
+	long  string
+	help  string
+}{
+	{"", "bash-completion", helpBashCompletion},
+	{"", "zsh-completion", helpZshCompletion},
+	{"", "fish-completion", helpFishCompletion},
+	{"h", "help", helpUsage},
+	{"", "version", helpVersion},
+	{"f", "field-delimiter-char", helpFieldDelimiterChar},
+	{"f-regex", "field-delimiter-regex", helpFieldDelimiterRe},
+	{"l", "line-delimiter-char", helpLineDelimiterChar},
+	{"n", "no-separate-rows", helpNoSeparateRows},
+	{"nb", "no-borders", helpNoBorders},
+	{"nh", "no-headers", helpNoHeaders},
+	{"fi", "filter-indexes", helpFilterIndexes},
+	{"j", "json", helpJSONOutput},
+	{"fmt", "format", helpFormat},
+	{"sy", "style", helpStyle},
+	{"th", "theme", helpTheme},
+	{"if", "input-format", helpInputFormat},
+	{"fw", "widths", helpWidths},
+	{"c", "color", helpColor},
+	{"a", "align", helpAlign},
+	{"mw", "max-width", helpMaxWidth},
+	{"wm", "width-mode", helpWidthMode},
+	{"ft", "footer", helpFooter},
+	{"gb", "group-by", helpGroupBy},
+	{"ag", "agg", helpAggregates},
+	{"rn", "rename", helpRename},
+	{"ac", "add-column", helpAddColumn},
+	{"jt", "json-types", helpJSONTypes},
+	{"js", "json-schema", helpJSONSchema},
+	{"p", "profile", helpProfile},
+	{"sc", "show-config", helpShowConfig},
+	{"rs", "raw-split", helpRawSplit},
+	{"s", "sort", helpSort},
+	{"w", "where", helpWhere},
+	{"st", "stream", helpStream},
+	{"sw", "stream-window", helpStreamWindow},
+	{"vw", "view", helpView},
+	{"o", "output", helpOutput},
+}
+
+// completionFlagDescription returns the help text of a flag suggestion.
+func completionFlagDescription(suggestion string) (string, bool) {
+	if !strings.HasPrefix(suggestion, "-") {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(strings.TrimPrefix(suggestion, "-"), "-")
+	for _, flag := range completionFlagHelp {
+		if name == flag.long || (name == flag.short && !strings.HasPrefix(suggestion, "--")) {
+			return flag.help, true
+		}
+	}
+
+	return "", false
+}
+
 type completionState struct {
-	fieldDelimiter rune
-	lineDelimiter  rune
+	fieldDelimiter string
+	fieldPattern   string
+	lineDelimiter  string
 	filterIndexes  bool
 	positionals    []string
 }
@@ -131,15 +363,57 @@ func bashCompletionScript(binaryName string) string {
                 continue
                 ;;
             -f|-field-delimiter-char|--field-delimiter-char|\
+            -f-regex|-field-delimiter-regex|--field-delimiter-regex|\
             -l|-line-delimiter-char|--line-delimiter-char|\
             -fi|-filter-indexes|--filter-indexes|\
+            -s|-sort|--sort|\
+            -w|-where|--where|\
+            -sw|-stream-window|--stream-window|\
+            -fmt|-format|--format|\
+            -sy|-style|--style|\
+            -th|-theme|--theme|\
+            -if|-input-format|--input-format|\
+            -fw|-widths|--widths|\
+            -c|-color|--color|\
+            -a|-align|--align|\
+            -mw|-max-width|--max-width|\
+            -wm|-width-mode|--width-mode|\
+            -ft|-footer|--footer|\
+            -gb|-group-by|--group-by|\
+            -ag|-agg|--agg|\
+            -rn|-rename|--rename|\
+            -ac|-add-column|--add-column|\
+            -jt|-json-types|--json-types|\
+            -js|-json-schema|--json-schema|\
+            -p|-profile|--profile|\
             -o|-output|--output)
                 expect_value=1
                 continue
                 ;;
             -field-delimiter-char=*|--field-delimiter-char=*|\
+            -field-delimiter-regex=*|--field-delimiter-regex=*|\
             -line-delimiter-char=*|--line-delimiter-char=*|\
             -filter-indexes=*|--filter-indexes=*|\
+            -sort=*|--sort=*|\
+            -where=*|--where=*|\
+            -stream-window=*|--stream-window=*|\
+            -format=*|--format=*|\
+            -style=*|--style=*|\
+            -theme=*|--theme=*|\
+            -input-format=*|--input-format=*|\
+            -widths=*|--widths=*|\
+            -color=*|--color=*|\
+            -align=*|--align=*|\
+            -max-width=*|--max-width=*|\
+            -width-mode=*|--width-mode=*|\
+            -footer=*|--footer=*|\
+            -group-by=*|--group-by=*|\
+            -agg=*|--agg=*|\
+            -rename=*|--rename=*|\
+            -add-column=*|--add-column=*|\
+            -json-types=*|--json-types=*|\
+            -json-schema=*|--json-schema=*|\
+            -profile=*|--profile=*|\
             -output=*|--output=*)
                 continue
                 ;;
@@ -148,7 +422,11 @@ func bashCompletionScript(binaryName string) string {
             -n|-no-separate-rows|--no-separate-rows|\
             -nb|-no-borders|--no-borders|\
             -nh|-no-headers|--no-headers|\
-            -j|-json|--json)
+            -j|-json|--json|\
+            -rs|-raw-split|--raw-split|\
+            -st|-stream|--stream|\
+            -vw|-view|--view|\
+            -sc|-show-config|--show-config)
                 continue
                 ;;
             -*)
@@ -198,6 +476,7 @@ func bashCompletionScript(binaryName string) string {
 
     case "${prev}" in
         -f|-field-delimiter-char|--field-delimiter-char|\
+        -f-regex|-field-delimiter-regex|--field-delimiter-regex|\
         -l|-line-delimiter-char|--line-delimiter-char|\
         -fi|-filter-indexes|--filter-indexes)
             return 0
@@ -215,7 +494,85 @@ complete -F _%[1]s_completion -- %[3]s
 `, functionName, completeFlag, quotedBinaryName)
 }
 
-func runCompletion(words []string, output io.Writer) error {
+func zshCompletionScript(binaryName string) string {
+	functionName := sanitizeCompletionFunctionName(binaryName)
+	quotedBinaryName := shellQuote(binaryName)
+
+	return fmt.Sprintf(`#compdef %[6]s
+
+_%[1]s() {
+    local line want_files=0 ret=1
+    local -a lines described values
+    lines=("${(@f)$(%[4]s=zsh COMP_CWORD=$(( CURRENT - 1 )) "${words[1]}" %[2]s -- "${words[@]}" 2>/dev/null)}")
+
+    for line in "${lines[@]}"; do
+        if [[ -z "${line}" ]]; then
+            continue
+        elif [[ "${line}" == %[5]s ]]; then
+            want_files=1
+        elif [[ "${line}" == *$'\t'* ]]; then
+            described+=("${line%%%%$'\t'*}:${line#*$'\t'}")
+        else
+            values+=("${line}")
+        fi
+    done
+
+    if (( ${#described[@]} > 0 )); then
+        _describe -t flags 'flag' described && ret=0
+    fi
+    if (( ${#values[@]} > 0 )); then
+        compadd -- "${values[@]}" && ret=0
+    fi
+    if (( want_files == 1 )); then
+        _files && ret=0
+    fi
+
+    return ret
+}
+
+if [[ "${funcstack[1]}" == "_%[1]s" ]]; then
+    _%[1]s "$@"
+else
+    compdef _%[1]s %[3]s
+fi
+`, functionName, completeFlag, quotedBinaryName, completionShellEnv, completionFilesMarker, binaryName)
+}
+
+func fishCompletionScript(binaryName string) string {
+	functionName := sanitizeCompletionFunctionName(binaryName)
+	quotedBinaryName := shellQuote(binaryName)
+
+	return fmt.Sprintf(`function __%[1]s_complete
+    set -l tokens (commandline -opc)
+    set -l current (commandline -ct)
+    set -l want_files 0
+
+    for line in (env %[4]s=fish COMP_CWORD=(count $tokens) $tokens[1] %[2]s -- $tokens "$current" 2>/dev/null)
+        if test "$line" = %[5]s
+            set want_files 1
+        else
+            printf '%%s\n' $line
+        end
+    end
+
+    if test $want_files = 1
+        __fish_complete_path "$current"
+    end
+end
+
+complete -c %[3]s -f -a '(__%[1]s_complete)'
+`, functionName, completeFlag, quotedBinaryName, completionShellEnv, completionFilesMarker)
+}
+
+func writeCompletionScript(w io.Writer, script string) error {
+	if _, err := fmt.Fprint(w, script); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return nil
+}
+
+func runCompletion(words []string, output io.Writer, getenv func(string) string) error {
 	if len(words) > 0 && words[0] == "--" {
 		words = words[1:]
 	}
@@ -223,7 +580,7 @@ func runCompletion(words []string, output io.Writer) error {
 		return nil
 	}
 
-	cword, err := strconv.Atoi(os.Getenv("COMP_CWORD"))
+	cword, err := strconv.Atoi(getenv("COMP_CWORD"))
 	if err != nil || cword < 0 {
 		cword = len(words) - 1
 	}
@@ -231,21 +588,67 @@ func runCompletion(words []string, output io.Writer) error {
 		cword = len(words) - 1
 	}
 
-	suggestions, err := completionSuggestions(words, cword)
+	suggestions, err := completionSuggestions(words, cword, getenv)
 	if err != nil {
 		return err
 	}
 
+	// bash gets plain suggestions, zsh and fish flag descriptions and the
+	// files marker.
+	describe := getenv(completionShellEnv) != ""
 	for _, suggestion := range suggestions {
+		if help, ok := completionFlagDescription(suggestion); ok && describe {
+			suggestion += "\t" + help
+		}
 		if _, err := fmt.Fprintln(output, suggestion); err != nil {
 			return fmt.Errorf(errorWrapFormat, err)
 		}
 	}
 
+	if !describe {
+		return nil
+	}
+	wantsFiles, err := completionWantsFiles(words, cword)
+	if err != nil || !wantsFiles {
+		return err
+	}
+	if _, err := fmt.Fprintln(output, completionFilesMarker); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
 	return nil
 }
 
-func completionSuggestions(words []string, cword int) ([]string, error) {
+// completionWantsFiles reports whether the current word is the input file or
+// the -o value, the shell completes both as paths.
+func completionWantsFiles(words []string, cword int) (bool, error) {
+	words, cword = joinEqualsTokens(words, cword)
+	words = dequoteCompletionWords(words)
+	if cword <= 0 || cword >= len(words) {
+		return false, nil
+	}
+
+	afterDoubleDash := completionAfterDoubleDash(words, cword)
+	if !afterDoubleDash {
+		switch previous := words[cword-1]; {
+		case previous == "-o" || previous == "-output" || previous == "--output":
+			return true, nil
+		case completionHasValueFlag(previous):
+			return false, nil
+		}
+	}
+
+	state := completionState{
+		lineDelimiter: string(defaultLineDelimiter),
+	}
+	if err := parseCompletionState(words, cword, &state); err != nil {
+		return false, err
+	}
+
+	return len(state.positionals) == 0 && (afterDoubleDash || !strings.HasPrefix(words[cword], "-")), nil
+}
+
+func completionSuggestions(words []string, cword int, getenv func(string) string) ([]string, error) {
 	words, cword = joinEqualsTokens(words, cword)
 	words = dequoteCompletionWords(words)
 
@@ -256,18 +659,18 @@ func completionSuggestions(words []string, cword int) ([]string, error) {
 	current := currentCompletionWord(words, cword)
 	afterDoubleDash := completionAfterDoubleDash(words, cword)
 	state := completionState{
-		lineDelimiter: defaultLineDelimiter,
+		lineDelimiter: string(defaultLineDelimiter),
 	}
 	if err := parseCompletionState(words, cword, &state); err != nil {
 		return nil, err
 	}
 	if !afterDoubleDash && len(state.positionals) == 0 {
-		if suggestions := completionInlineValueSuggestions(current); suggestions != nil {
+		if suggestions := completionInlineValueSuggestions(current, getenv); suggestions != nil {
 			return suggestions, nil
 		}
 
 		previous := words[cword-1]
-		if suggestions := completionValueSuggestions(previous, current); suggestions != nil {
+		if suggestions := completionValueSuggestions(previous, current, getenv); suggestions != nil {
 			return suggestions, nil
 		}
 	}
@@ -280,7 +683,7 @@ func completionSuggestions(words []string, cword int) ([]string, error) {
 	if state.filterIndexes || len(state.positionals) == 0 {
 		return nil, nil
 	}
-	resolvedPath, err := resolveCompletionPath(state.positionals[0])
+	resolvedPath, err := resolveCompletionPath(state.positionals[0], getenv)
 	if err != nil {
 		return nil, err
 	}
@@ -288,7 +691,7 @@ func completionSuggestions(words []string, cword int) ([]string, error) {
 		return nil, nil
 	}
 
-	return completeColumnsFromFile(state, resolvedPath, state.positionals[1:], current)
+	return completeColumnsFromFile(state, resolvedPath, state.positionals[1:], current, getenv)
 }
 
 func completionAfterDoubleDash(words []string, cword int) bool {
@@ -430,24 +833,43 @@ func completionHasValueFlag(flagName string) bool {
 func applyCompletionFlagValue(state *completionState, flagName, value string) {
 	switch flagName {
 	case "-f", "-field-delimiter-char", "--field-delimiter-char":
-		if value != "" {
-			state.fieldDelimiter = parseSpecialChars(value)
-		}
+		state.fieldDelimiter = value
+	case "-f-regex", "-field-delimiter-regex", "--field-delimiter-regex":
+		state.fieldPattern = value
 	case "-l", "-line-delimiter-char", "--line-delimiter-char":
-		if value != "" {
-			state.lineDelimiter = parseSpecialChars(value)
+		if value == "" {
+			break
+		}
+		if delimiter, err := parseEscapes(value); err == nil {
+			state.lineDelimiter = delimiter
 		}
 	case "-fi", "-filter-indexes", "--filter-indexes":
 		state.filterIndexes = value != ""
 	}
 }
 
-func completionValueSuggestions(flagName, current string) []string {
+func completionValueSuggestions(flagName, current string, getenv func(string) string) []string {
 	switch flagName {
 	case "-f", "-field-delimiter-char", "--field-delimiter-char":
 		return completionPrefixMatches([]string{",", ";", "|", ":", "\\t"}, current)
 	case "-l", "-line-delimiter-char", "--line-delimiter-char":
-		return completionPrefixMatches([]string{"\\n", "\\t", "\\r", ":", ";", "|"}, current)
+		return completionPrefixMatches([]string{"\\n", "\\t", "\\r", "\\0", ":", ";", "|"}, current)
+	case "-fmt", "-format", "--format":
+		return completionPrefixMatches(outputFormatNames(), current)
+	case "-sy", "-style", "--style":
+		return completionPrefixMatches(styleNames(), current)
+	case "-th", "-theme", "--theme":
+		return completionPrefixMatches(themeNames(), current)
+	case "-if", "-input-format", "--input-format":
+		return completionPrefixMatches(inputFormatNames(), current)
+	case "-wm", "-width-mode", "--width-mode":
+		return completionPrefixMatches(widthModeNames(), current)
+	case "-c", "-color", "--color":
+		return completionPrefixMatches(colorModeNames(), current)
+	case "-jt", "-json-types", "--json-types":
+		return completionPrefixMatches(jsonTypesNames(), current)
+	case "-p", "-profile", "--profile":
+		return completionPrefixMatches(configProfileNames(getenv), current)
 	default:
 		return nil
 	}
@@ -457,7 +879,7 @@ func completionFlagMatches(current string) []string {
 	return completionPrefixMatches(completionAllFlags, current)
 }
 
-func completionInlineValueSuggestions(current string) []string {
+func completionInlineValueSuggestions(current string, getenv func(string) string) []string {
 	flagName, currentValue, hasInlineValue := completionFlagToken(current)
 	if !hasInlineValue {
 		return nil
@@ -467,7 +889,7 @@ func completionInlineValueSuggestions(current string) []string {
 		return nil
 	}
 
-	suggestions := completionValueSuggestions(flagName, currentValue)
+	suggestions := completionValueSuggestions(flagName, currentValue, getenv)
 	if suggestions == nil {
 		return nil
 	}
@@ -540,8 +962,10 @@ func shellQuote(value string) string {
 	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
 }
 
-func completeColumnsFromFile(state completionState, path string, selected []string, current string) ([]string, error) {
-	resolvedPath, err := resolveCompletionPath(path)
+func completeColumnsFromFile(
+	state completionState, path string, selected []string, current string, getenv func(string) string,
+) ([]string, error) {
+	resolvedPath, err := resolveCompletionPath(path, getenv)
 	if err != nil {
 		return nil, err
 	}
@@ -560,8 +984,12 @@ func completeColumnsFromFile(state completionState, path string, selected []stri
 		return nil, nil
 	}
 
-	tbl := &Tablo{
-		FieldDelimiter: state.fieldDelimiter,
+	tbl := &Tablo{}
+	if err = WithFieldDelimiter(state.fieldDelimiter)(tbl); err != nil {
+		return nil, err
+	}
+	if err = WithFieldDelimiterRegex(state.fieldPattern)(tbl); err != nil {
+		return nil, err
 	}
 	tbl.ensureDetectedFieldDelimiter(lines)
 
@@ -588,33 +1016,33 @@ func completeColumnsFromFile(state completionState, path string, selected []stri
 	return suggestions, nil
 }
 
-func resolveCompletionPath(path string) (string, error) {
+func resolveCompletionPath(path string, getenv func(string) string) (string, error) {
 	if path == "" {
 		return "", nil
 	}
 
-	expanded := os.ExpandEnv(path)
+	expanded := os.Expand(path, getenv)
 	if expanded == "~" {
-		homeDir, err := os.UserHomeDir()
+		home, err := homeDir(getenv)
 		if err != nil {
-			return "", fmt.Errorf(errorWrapFormat, err)
+			return "", err
 		}
 
-		return homeDir, nil
+		return home, nil
 	}
 	if strings.HasPrefix(expanded, "~/") {
-		homeDir, err := os.UserHomeDir()
+		home, err := homeDir(getenv)
 		if err != nil {
-			return "", fmt.Errorf(errorWrapFormat, err)
+			return "", err
 		}
 
-		return filepath.Join(homeDir, strings.TrimPrefix(expanded, "~/")), nil
+		return filepath.Join(home, strings.TrimPrefix(expanded, "~/")), nil
 	}
 
 	return expanded, nil
 }
 
-func readCompletionLines(reader io.Reader, delimiter rune, limit int) ([]string, error) {
+func readCompletionLines(reader io.Reader, delimiter string, limit int) ([]string, error) {
 	if limit <= 0 {
 		return nil, nil
 	}
@@ -625,9 +1053,9 @@ func readCompletionLines(reader io.Reader, delimiter rune, limit int) ([]string,
 	bytesRead := 0
 
 	flushLine := func() {
-		line := currentLine.String()
+		line := strings.TrimSuffix(currentLine.String(), delimiter)
 		currentLine.Reset()
-		if delimiter == '\n' {
+		if delimiter == string(defaultLineDelimiter) {
 			line = strings.TrimSuffix(line, "\r")
 		}
 
@@ -649,11 +1077,10 @@ func readCompletionLines(reader io.Reader, delimiter rune, limit int) ([]string,
 				}
 				return lines, nil
 			}
-			if r == delimiter {
+			currentLine.WriteRune(r)
+			if strings.HasSuffix(currentLine.String(), delimiter) {
 				flushLine()
-				continue
 			}
-			currentLine.WriteRune(r)
 		case io.EOF:
 			if currentLine.Len() > 0 {
 				flushLine()
@@ -2,9 +2,8 @@ package tablo
 
 import (
 	"bytes"
+	"context"
 	"errors"
-	"flag"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -55,6 +54,83 @@ func TestBashCompletionScript_UsesSanitizedFunctionName(t *testing.T) {
 	assert.NotContains(t, script, "_tablo-dev_completion() {")
 }
 
+func TestZshCompletionScript(t *testing.T) {
+	script := zshCompletionScript("tablo-dev")
+
+	assert.True(t, strings.HasPrefix(script, "#compdef tablo-dev\n"))
+	assert.Contains(t, script, "_tablo_dev() {")
+	assert.Contains(t, script, `COMP_SHELL=zsh COMP_CWORD=$(( CURRENT - 1 )) "${words[1]}" --complete --`)
+	assert.Contains(t, script, `elif [[ "${line}" == :files ]]; then`)
+	assert.Contains(t, script, "_describe -t flags 'flag' described")
+	assert.Contains(t, script, "compdef _tablo_dev 'tablo-dev'")
+}
+
+func TestFishCompletionScript(t *testing.T) {
+	script := fishCompletionScript("tablo-dev")
+
+	assert.Contains(t, script, "function __tablo_dev_complete")
+	assert.Contains(t, script, "env COMP_SHELL=fish COMP_CWORD=(count $tokens) $tokens[1]")
+	assert.Contains(t, script, `--complete -- $tokens "$current" 2>/dev/null`)
+	assert.Contains(t, script, `__fish_complete_path "$current"`)
+	assert.Contains(t, script, "complete -c 'tablo-dev' -f -a '(__tablo_dev_complete)'")
+}
+
+func TestCompletionFlagDescription(t *testing.T) {
+	tests := []struct {
+		suggestion string
+		help       string
+		ok         bool
+	}{
+		{"-f", helpFieldDelimiterChar, true},
+		{"--field-delimiter-char", helpFieldDelimiterChar, true},
+		{"-zsh-completion", helpZshCompletion, true},
+		{"--f", "", false},
+		{"--format=csv", "", false},
+		{"Username", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.suggestion, func(t *testing.T) {
+			help, ok := completionFlagDescription(tt.suggestion)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.help, help)
+		})
+	}
+}
+
+func TestCompletionFlagHelp_CoversAllFlags(t *testing.T) {
+	for _, flag := range completionAllFlags {
+		_, ok := completionFlagDescription(flag)
+		assert.True(t, ok, flag)
+	}
+}
+
+func TestCompletionWantsFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		want  bool
+	}{
+		{"first positional", []string{"tablo", "-n", "us"}, true},
+		{"empty first word", []string{"tablo", ""}, true},
+		{"flag", []string{"tablo", "-n"}, false},
+		{"output value", []string{"tablo", "-o", ""}, true},
+		{"flag value", []string{"tablo", "-f", ""}, false},
+		{"column", []string{"tablo", "users.csv", ""}, false},
+		{"after double dash", []string{"tablo", "--", "-weird.csv"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := completionWantsFiles(tt.words, len(tt.words)-1)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, want)
+		})
+	}
+}
+
 func TestShellQuote(t *testing.T) {
 	assert.Equal(t, "'tablo dev'", shellQuote("tablo dev"))
 	assert.Equal(t, `'tablo'\''dev'`, shellQuote("tablo'dev"))
@@ -66,24 +142,24 @@ func TestResolveCompletionPath(t *testing.T) {
 
 	t.Setenv("TABLO_TEST_DIR", "tablo")
 
-	path, err := resolveCompletionPath("~/data.csv")
+	path, err := resolveCompletionPath("~/data.csv", os.Getenv)
 	require.NoError(t, err)
 	assert.Equal(t, filepath.Join(homeDir, "data.csv"), path)
 
-	path, err = resolveCompletionPath("$TABLO_TEST_DIR/data.csv")
+	path, err = resolveCompletionPath("$TABLO_TEST_DIR/data.csv", os.Getenv)
 	require.NoError(t, err)
 	assert.Equal(t, filepath.Join("tablo", "data.csv"), path)
 }
 
 func TestCompletionSuggestions_Flags(t *testing.T) {
-	suggestions, err := completionSuggestions([]string{"tablo", "--j"}, 1)
+	suggestions, err := completionSuggestions([]string{"tablo", "--j"}, 1, os.Getenv)
 
 	require.NoError(t, err)
-	assert.Equal(t, []string{"--json"}, suggestions)
+	assert.Equal(t, []string{"--json", "--json-types", "--json-schema"}, suggestions)
 }
 
 func TestCompletionSuggestions_AllFlagsForFirstArgument(t *testing.T) {
-	suggestions, err := completionSuggestions([]string{"tablo", ""}, 1)
+	suggestions, err := completionSuggestions([]string{"tablo", ""}, 1, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Contains(t, suggestions, shortBashCompletionFlag)
@@ -95,7 +171,7 @@ func TestCompletionSuggestions_AllFlagsForFirstArgument(t *testing.T) {
 }
 
 func TestCompletionSuggestions_FieldDelimiterValues(t *testing.T) {
-	suggestions, err := completionSuggestions([]string{"tablo", "-f", ":"}, 2)
+	suggestions, err := completionSuggestions([]string{"tablo", "-f", ":"}, 2, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Contains(t, suggestions, ":")
@@ -103,28 +179,28 @@ func TestCompletionSuggestions_FieldDelimiterValues(t *testing.T) {
 }
 
 func TestCompletionSuggestions_LineDelimiterValues(t *testing.T) {
-	suggestions, err := completionSuggestions([]string{"tablo", "-l", "\\r"}, 2)
+	suggestions, err := completionSuggestions([]string{"tablo", "-l", "\\r"}, 2, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"\\r"}, suggestions)
 }
 
 func TestCompletionSuggestions_LongFieldDelimiterValues(t *testing.T) {
-	suggestions, err := completionSuggestions([]string{"tablo", "-field-delimiter-char", ":"}, 2)
+	suggestions, err := completionSuggestions([]string{"tablo", "-field-delimiter-char", ":"}, 2, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{":"}, suggestions)
 }
 
 func TestCompletionSuggestions_InlineFieldDelimiterValues(t *testing.T) {
-	suggestions, err := completionSuggestions([]string{"tablo", "--field-delimiter-char=:"}, 1)
+	suggestions, err := completionSuggestions([]string{"tablo", "--field-delimiter-char=:"}, 1, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"--field-delimiter-char=:"}, suggestions)
 }
 
 func TestCompletionSuggestions_InlineLineDelimiterValues(t *testing.T) {
-	suggestions, err := completionSuggestions([]string{"tablo", "--line-delimiter-char=\\r"}, 1)
+	suggestions, err := completionSuggestions([]string{"tablo", "--line-delimiter-char=\\r"}, 1, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"--line-delimiter-char=\\r"}, suggestions)
@@ -136,7 +212,7 @@ func TestCompletionSuggestions_AfterPositionalDoesNotSuggestInlineFlagValues(t *
 	err := os.WriteFile(inputFile, []byte("Username;Identifier\nbooker12;9012\n"), 0o600)
 	require.NoError(t, err)
 
-	suggestions, err := completionSuggestions([]string{"tablo", inputFile, "--field-delimiter-char=:"}, 2)
+	suggestions, err := completionSuggestions([]string{"tablo", inputFile, "--field-delimiter-char=:"}, 2, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Nil(t, suggestions)
@@ -148,28 +224,28 @@ func TestCompletionSuggestions_NoColumnCompletionWhenFilterIndexesSet(t *testing
 	err := os.WriteFile(inputFile, []byte("Username;Identifier\nbooker12;9012\n"), 0o600)
 	require.NoError(t, err)
 
-	suggestions, err := completionSuggestions([]string{"tablo", "-fi", "1,2", inputFile, ""}, 4)
+	suggestions, err := completionSuggestions([]string{"tablo", "-fi", "1,2", inputFile, ""}, 4, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Nil(t, suggestions)
 }
 
 func TestCompletionSuggestions_NoColumnCompletionWhenFirstPositionalIsNotFile(t *testing.T) {
-	suggestions, err := completionSuggestions([]string{"tablo", "Username"}, 1)
+	suggestions, err := completionSuggestions([]string{"tablo", "Username"}, 1, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Nil(t, suggestions)
 }
 
 func TestCompletionSuggestions_AfterDoubleDashDoesNotSuggestFlags(t *testing.T) {
-	suggestions, err := completionSuggestions([]string{"tablo", "--", "-weird.csv"}, 2)
+	suggestions, err := completionSuggestions([]string{"tablo", "--", "-weird.csv"}, 2, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Nil(t, suggestions)
 }
 
 func TestCompletionSuggestions_AfterDoubleDashDoesNotSuggestFlagValues(t *testing.T) {
-	suggestions, err := completionSuggestions([]string{"tablo", "--", "-f"}, 2)
+	suggestions, err := completionSuggestions([]string{"tablo", "--", "-f"}, 2, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Nil(t, suggestions)
@@ -181,14 +257,14 @@ func TestCompletionSuggestions_AfterPositionalDoesNotSuggestFlags(t *testing.T)
 	err := os.WriteFile(inputFile, []byte("Username;Identifier\nbooker12;9012\n"), 0o600)
 	require.NoError(t, err)
 
-	suggestions, err := completionSuggestions([]string{"tablo", inputFile, "-n"}, 2)
+	suggestions, err := completionSuggestions([]string{"tablo", inputFile, "-n"}, 2, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Nil(t, suggestions)
 }
 
 func TestCompletionSuggestions_AfterDoubleDashDoesNotSuggestInlineFlagValues(t *testing.T) {
-	suggestions, err := completionSuggestions([]string{"tablo", "--", "--field-delimiter-char=:"}, 2)
+	suggestions, err := completionSuggestions([]string{"tablo", "--", "--field-delimiter-char=:"}, 2, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Nil(t, suggestions)
@@ -200,7 +276,7 @@ func TestCompletionSuggestions_ColumnsFromInputFile(t *testing.T) {
 	err := os.WriteFile(inputFile, []byte("Username;Identifier;First name\nbooker12;9012;Rachel\n"), 0o600)
 	require.NoError(t, err)
 
-	suggestions, err := completionSuggestions([]string{"tablo", "-f", ";", inputFile, "Fi"}, 4)
+	suggestions, err := completionSuggestions([]string{"tablo", "-f", ";", inputFile, "Fi"}, 4, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"First name"}, suggestions)
@@ -212,7 +288,7 @@ func TestCompletionSuggestions_ColumnsFromInputFile_AutoDetectDelimiter(t *testi
 	err := os.WriteFile(inputFile, []byte("Username,Identifier,First name\nbooker12,9012,Rachel\n"), 0o600)
 	require.NoError(t, err)
 
-	suggestions, err := completionSuggestions([]string{"tablo", inputFile, "Id"}, 2)
+	suggestions, err := completionSuggestions([]string{"tablo", inputFile, "Id"}, 2, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"Identifier"}, suggestions)
@@ -224,7 +300,7 @@ func TestCompletionSuggestions_ColumnsExcludeAlreadySelected(t *testing.T) {
 	err := os.WriteFile(inputFile, []byte("Username;Identifier;First name\nbooker12;9012;Rachel\n"), 0o600)
 	require.NoError(t, err)
 
-	suggestions, err := completionSuggestions([]string{"tablo", "-f", ";", inputFile, "Username", ""}, 5)
+	suggestions, err := completionSuggestions([]string{"tablo", "-f", ";", inputFile, "Username", ""}, 5, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"Identifier", "First name"}, suggestions)
@@ -236,7 +312,7 @@ func TestCompletionSuggestions_DequotesDoubleQuotedFieldDelimiterValue(t *testin
 	err := os.WriteFile(inputFile, []byte("Username,Identifier,First name\nbooker12,9012,Rachel\n"), 0o600)
 	require.NoError(t, err)
 
-	suggestions, err := completionSuggestions([]string{"tablo", "-f", `","`, inputFile, ""}, 4)
+	suggestions, err := completionSuggestions([]string{"tablo", "-f", `","`, inputFile, ""}, 4, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"Username", "Identifier", "First name"}, suggestions)
@@ -248,7 +324,7 @@ func TestCompletionSuggestions_DequotesSingleQuotedFieldDelimiterValue(t *testin
 	err := os.WriteFile(inputFile, []byte("Username;Identifier;First name\nbooker12;9012;Rachel\n"), 0o600)
 	require.NoError(t, err)
 
-	suggestions, err := completionSuggestions([]string{"tablo", "-f", `';'`, inputFile, ""}, 4)
+	suggestions, err := completionSuggestions([]string{"tablo", "-f", `';'`, inputFile, ""}, 4, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"Username", "Identifier", "First name"}, suggestions)
@@ -261,7 +337,7 @@ func TestCompletionSuggestions_DequotesQuotedInputFilePath(t *testing.T) {
 	require.NoError(t, err)
 
 	quotedPath := `"` + inputFile + `"`
-	suggestions, err := completionSuggestions([]string{"tablo", "-f", ",", quotedPath, ""}, 4)
+	suggestions, err := completionSuggestions([]string{"tablo", "-f", ",", quotedPath, ""}, 4, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"Username", "Identifier"}, suggestions)
@@ -273,7 +349,7 @@ func TestCompletionSuggestions_DequotesInlineFieldDelimiterValue(t *testing.T) {
 	err := os.WriteFile(inputFile, []byte("Username;Identifier\nbooker12;9012\n"), 0o600)
 	require.NoError(t, err)
 
-	suggestions, err := completionSuggestions([]string{"tablo", `--field-delimiter-char=";"`, inputFile, ""}, 3)
+	suggestions, err := completionSuggestions([]string{"tablo", `--field-delimiter-char=";"`, inputFile, ""}, 3, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"Username", "Identifier"}, suggestions)
@@ -291,6 +367,7 @@ func TestCompletionSuggestions_SplitLongInlineFieldDelimiterValue(t *testing.T)
 	suggestions, err := completionSuggestions(
 		[]string{"tablo", "--field-delimiter-char", "=", `";"`, inputFile, ""},
 		5,
+		os.Getenv,
 	)
 
 	require.NoError(t, err)
@@ -306,6 +383,7 @@ func TestCompletionSuggestions_SplitShortInlineFieldDelimiterValue(t *testing.T)
 	suggestions, err := completionSuggestions(
 		[]string{"tablo", "-f", "=", `","`, inputFile, ""},
 		5,
+		os.Getenv,
 	)
 
 	require.NoError(t, err)
@@ -318,6 +396,7 @@ func TestCompletionSuggestions_SplitInlineValueSuggestion(t *testing.T) {
 	suggestions, err := completionSuggestions(
 		[]string{"tablo", "--field-delimiter-char", "=", ""},
 		3,
+		os.Getenv,
 	)
 
 	require.NoError(t, err)
@@ -440,7 +519,7 @@ func TestDequoteCompletionToken(t *testing.T) {
 func TestRunCompletion_NoWords(t *testing.T) {
 	var output bytes.Buffer
 
-	err := runCompletion(nil, &output)
+	err := runCompletion(nil, &output, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Empty(t, output.String())
@@ -455,7 +534,7 @@ func TestRunCompletion_WritesSuggestions(t *testing.T) {
 	t.Setenv("COMP_CWORD", "4")
 
 	var output bytes.Buffer
-	err = runCompletion([]string{"--", "tablo", "-f", ";", inputFile, "Us"}, &output)
+	err = runCompletion([]string{"--", "tablo", "-f", ";", inputFile, "Us"}, &output, os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, "Username", strings.TrimSpace(output.String()))
@@ -465,52 +544,68 @@ func TestRunCompletion_InvalidCompCwordFallsBack(t *testing.T) {
 	t.Setenv("COMP_CWORD", "invalid")
 
 	var output bytes.Buffer
-	err := runCompletion([]string{"--", "tablo", "--j"}, &output)
+	err := runCompletion([]string{"--", "tablo", "--j"}, &output, os.Getenv)
+
+	require.NoError(t, err)
+	assert.Equal(t, "--json\n--json-types\n--json-schema", strings.TrimSpace(output.String()))
+}
+
+func TestRunCompletion_DescribesFlags(t *testing.T) {
+	env := []string{completionShellEnv + "=fish"}
+
+	var output bytes.Buffer
+	err := runCompletion([]string{"--", "tablo", "--json-s"}, &output, envLookup(env))
+
+	require.NoError(t, err)
+	assert.Equal(t, "--json-schema\t"+helpJSONSchema+"\n", output.String())
+
+	output.Reset()
+	err = runCompletion([]string{"--", "tablo", "-n", "us"}, &output, envLookup(append(env, "COMP_CWORD=2")))
 
 	require.NoError(t, err)
-	assert.Equal(t, "--json", strings.TrimSpace(output.String()))
+	assert.Equal(t, completionFilesMarker+"\n", output.String())
 }
 
 func TestRunCompletion_ReturnsWriteError(t *testing.T) {
 	writeErr := errors.New("write failed")
 	t.Setenv("COMP_CWORD", "2")
 
-	err := runCompletion([]string{"--", "tablo", "--j"}, failingWriter{err: writeErr})
+	err := runCompletion([]string{"--", "tablo", "--j"}, failingWriter{err: writeErr}, os.Getenv)
 
 	assert.ErrorIs(t, err, writeErr)
 }
 
 func TestParseCompletionState(t *testing.T) {
 	state := completionState{
-		lineDelimiter: defaultLineDelimiter,
+		lineDelimiter: string(defaultLineDelimiter),
 	}
 
 	err := parseCompletionState([]string{"tablo", "--field-delimiter-char=;", "-l", "\\t", "-fi", "1,2", "--json", "users.csv", "Username"}, 8, &state)
 
 	require.NoError(t, err)
-	assert.Equal(t, ';', state.fieldDelimiter)
-	assert.Equal(t, '\t', state.lineDelimiter)
+	assert.Equal(t, ";", state.fieldDelimiter)
+	assert.Equal(t, "\t", state.lineDelimiter)
 	assert.True(t, state.filterIndexes)
 	assert.Equal(t, []string{"users.csv"}, state.positionals)
 }
 
 func TestParseCompletionState_SingleDashLongFlags(t *testing.T) {
 	state := completionState{
-		lineDelimiter: defaultLineDelimiter,
+		lineDelimiter: string(defaultLineDelimiter),
 	}
 
 	err := parseCompletionState([]string{"tablo", "-field-delimiter-char=;", "-line-delimiter-char", "\\t", "-filter-indexes", "1,2", "-json", "users.csv"}, 7, &state)
 
 	require.NoError(t, err)
-	assert.Equal(t, ';', state.fieldDelimiter)
-	assert.Equal(t, '\t', state.lineDelimiter)
+	assert.Equal(t, ";", state.fieldDelimiter)
+	assert.Equal(t, "\t", state.lineDelimiter)
 	assert.True(t, state.filterIndexes)
 	assert.Empty(t, state.positionals)
 }
 
 func TestParseCompletionState_EndOfFlags(t *testing.T) {
 	state := completionState{
-		lineDelimiter: defaultLineDelimiter,
+		lineDelimiter: string(defaultLineDelimiter),
 	}
 
 	err := parseCompletionState([]string{"tablo", "--", "users.csv", "Username"}, 4, &state)
@@ -521,7 +616,7 @@ func TestParseCompletionState_EndOfFlags(t *testing.T) {
 
 func TestParseCompletionState_StopsAtFirstPositional(t *testing.T) {
 	state := completionState{
-		lineDelimiter: defaultLineDelimiter,
+		lineDelimiter: string(defaultLineDelimiter),
 	}
 
 	err := parseCompletionState([]string{"tablo", "users.csv", "-o", "out.txt"}, 4, &state)
@@ -544,7 +639,7 @@ func TestCompletionFlagToken(t *testing.T) {
 
 func TestApplyCompletionFlagValue(t *testing.T) {
 	state := completionState{
-		lineDelimiter: defaultLineDelimiter,
+		lineDelimiter: string(defaultLineDelimiter),
 	}
 
 	applyCompletionFlagValue(&state, "--line-delimiter-char", "\\r")
@@ -552,37 +647,55 @@ func TestApplyCompletionFlagValue(t *testing.T) {
 	applyCompletionFlagValue(&state, "--filter-indexes", "1")
 	applyCompletionFlagValue(&state, "--filter-indexes", "")
 
-	assert.Equal(t, '\r', state.lineDelimiter)
-	assert.Equal(t, '|', state.fieldDelimiter)
+	assert.Equal(t, "\r", state.lineDelimiter)
+	assert.Equal(t, "|", state.fieldDelimiter)
 	assert.False(t, state.filterIndexes)
 }
 
 func TestApplyCompletionFlagValue_SingleDashLongFlags(t *testing.T) {
 	state := completionState{
-		lineDelimiter: defaultLineDelimiter,
+		lineDelimiter: string(defaultLineDelimiter),
 	}
 
 	applyCompletionFlagValue(&state, "-line-delimiter-char", "\\r")
 	applyCompletionFlagValue(&state, "-field-delimiter-char", ";")
 	applyCompletionFlagValue(&state, "-filter-indexes", "2")
+	applyCompletionFlagValue(&state, "-field-delimiter-regex", `\s*;\s*`)
+	applyCompletionFlagValue(&state, "-line-delimiter-char", `\q`)
 
-	assert.Equal(t, '\r', state.lineDelimiter)
-	assert.Equal(t, ';', state.fieldDelimiter)
+	assert.Equal(t, "\r", state.lineDelimiter)
+	assert.Equal(t, ";", state.fieldDelimiter)
+	assert.Equal(t, `\s*;\s*`, state.fieldPattern)
 	assert.True(t, state.filterIndexes)
 }
 
+func TestCompletionValueSuggestions_Format(t *testing.T) {
+	assert.Equal(t, []string{"markdown"}, completionValueSuggestions("-fmt", "m", os.Getenv))
+	assert.Equal(t, []string{"table", "tsv"}, completionValueSuggestions("--format", "t", os.Getenv))
+}
+
+func TestCompletionValueSuggestions_Profile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	content := "[profiles.passwd]\nno-borders = true\n[profiles.ps]\n[profiles.docker]\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv(configEnvPath, path)
+
+	assert.Equal(t, []string{"passwd", "ps"}, completionValueSuggestions("-p", "p", os.Getenv))
+	assert.Equal(t, []string{"docker"}, completionValueSuggestions("--profile", "d", os.Getenv))
+}
+
 func TestCompletionValueSuggestions_UnknownFlag(t *testing.T) {
-	assert.Nil(t, completionValueSuggestions("--unknown", ""))
+	assert.Nil(t, completionValueSuggestions("--unknown", "", os.Getenv))
 }
 
 func TestCompletionInlineValueSuggestions(t *testing.T) {
 	assert.Equal(
 		t,
 		[]string{"--field-delimiter-char=:"},
-		completionInlineValueSuggestions("--field-delimiter-char=:"),
+		completionInlineValueSuggestions("--field-delimiter-char=:", os.Getenv),
 	)
-	assert.Nil(t, completionInlineValueSuggestions("--output=foo"))
-	assert.Nil(t, completionInlineValueSuggestions("--json"))
+	assert.Nil(t, completionInlineValueSuggestions("--output=foo", os.Getenv))
+	assert.Nil(t, completionInlineValueSuggestions("--json", os.Getenv))
 }
 
 func TestCompletionPrefixMatches(t *testing.T) {
@@ -611,7 +724,7 @@ func TestCompleteColumnsFromFile_CommentOnlyInput(t *testing.T) {
 	err := os.WriteFile(inputFile, []byte("# comment\n"), 0o600)
 	require.NoError(t, err)
 
-	suggestions, err := completeColumnsFromFile(completionState{lineDelimiter: '\n'}, inputFile, nil, "")
+	suggestions, err := completeColumnsFromFile(completionState{lineDelimiter: "\n"}, inputFile, nil, "", os.Getenv)
 
 	require.NoError(t, err)
 	assert.Nil(t, suggestions)
@@ -633,7 +746,7 @@ func TestCompleteColumnsFromFile_ReadsOnlyHeaderProbeLines(t *testing.T) {
 	err := os.WriteFile(inputFile, []byte(content), 0o600)
 	require.NoError(t, err)
 
-	suggestions, err := completeColumnsFromFile(completionState{lineDelimiter: '\n'}, inputFile, nil, "Fi")
+	suggestions, err := completeColumnsFromFile(completionState{lineDelimiter: "\n"}, inputFile, nil, "Fi", os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"First name"}, suggestions)
@@ -650,9 +763,9 @@ func TestCompleteColumnsFromFile_WithoutHeaderReturnsNoSuggestions(t *testing.T)
 	require.NoError(t, err)
 
 	suggestions, err := completeColumnsFromFile(completionState{
-		lineDelimiter:  '\n',
-		fieldDelimiter: ';',
-	}, inputFile, nil, "Ra")
+		lineDelimiter:  "\n",
+		fieldDelimiter: ";",
+	}, inputFile, nil, "Ra", os.Getenv)
 
 	require.NoError(t, err)
 	assert.Nil(t, suggestions)
@@ -667,16 +780,55 @@ func TestCompleteColumnsFromFile_ResolvesHomePath(t *testing.T) {
 	require.NoError(t, err)
 
 	suggestions, err := completeColumnsFromFile(completionState{
-		lineDelimiter:  '\n',
-		fieldDelimiter: ';',
-	}, "~/tablo-completion-home.csv", nil, "Us")
+		lineDelimiter:  "\n",
+		fieldDelimiter: ";",
+	}, "~/tablo-completion-home.csv", nil, "Us", os.Getenv)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"Username"}, suggestions)
 }
 
+func TestCompleteColumnsFromFile_MultiCharacterAndRegexDelimiters(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "tablo-completion.txt")
+	err := os.WriteFile(inputFile, []byte("Username :: Identifier\nbooker12 :: 9012\n"), 0o600)
+	require.NoError(t, err)
+
+	suggestions, err := completeColumnsFromFile(completionState{
+		lineDelimiter:  "\n",
+		fieldDelimiter: " :: ",
+	}, inputFile, nil, "Id", os.Getenv)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Identifier"}, suggestions)
+
+	suggestions, err = completeColumnsFromFile(completionState{
+		lineDelimiter: "\n",
+		fieldPattern:  `\s*::\s*`,
+	}, inputFile, []string{"Username"}, "", os.Getenv)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Identifier"}, suggestions)
+}
+
 func TestReadCompletionLines(t *testing.T) {
-	lines, err := readCompletionLines(strings.NewReader("# comment\n\nname,age\nvigo,42\nignored,after\n"), '\n', 2)
+	lines, err := readCompletionLines(strings.NewReader("# comment\n\nname,age\nvigo,42\nignored,after\n"), "\n", 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name,age", "vigo,42"}, lines)
+}
+
+func TestReadCompletionLines_Delimiters(t *testing.T) {
+	lines, err := readCompletionLines(strings.NewReader("name,age\r\nvigo,42\r\n"), "\n", 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name,age", "vigo,42"}, lines)
+
+	lines, err = readCompletionLines(strings.NewReader("name,age;;vigo,42;;"), ";;", 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name,age", "vigo,42"}, lines)
+
+	lines, err = readCompletionLines(strings.NewReader("name,age\x00vigo,42"), "\x00", 2)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"name,age", "vigo,42"}, lines)
@@ -685,7 +837,7 @@ func TestReadCompletionLines(t *testing.T) {
 func TestReadCompletionLines_StopsAtByteCap(t *testing.T) {
 	veryLong := strings.Repeat("a", completionByteCap+10)
 
-	lines, err := readCompletionLines(strings.NewReader(veryLong), '\n', 2)
+	lines, err := readCompletionLines(strings.NewReader(veryLong), "\n", 2)
 
 	require.NoError(t, err)
 	require.Len(t, lines, 1)
@@ -693,7 +845,7 @@ func TestReadCompletionLines_StopsAtByteCap(t *testing.T) {
 }
 
 func TestCompleteColumnsFromFile_OpenError(t *testing.T) {
-	_, err := completeColumnsFromFile(completionState{lineDelimiter: '\n'}, "/does/not/exist.csv", nil, "")
+	_, err := completeColumnsFromFile(completionState{lineDelimiter: "\n"}, "/does/not/exist.csv", nil, "", os.Getenv)
 
 	assert.Error(t, err)
 }
@@ -704,27 +856,11 @@ func TestRun_CompleteFlag(t *testing.T) {
 	err := os.WriteFile(inputFile, []byte("Username;Identifier\nbooker12;9012\n"), 0o600)
 	require.NoError(t, err)
 
-	t.Setenv("COMP_CWORD", "4")
-	oldArgs := os.Args
-	oldFlagSet := flag.CommandLine
-	defer func() {
-		os.Args = oldArgs
-		flag.CommandLine = oldFlagSet
-	}()
-	os.Args = []string{"tablo", "--complete", "--", "tablo", "-f", ";", inputFile, "Us"}
-	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
-
-	oldStdout := os.Stdout
-	r, w, err := os.Pipe()
-	require.NoError(t, err)
-	os.Stdout = w
-	defer func() { os.Stdout = oldStdout }()
-
-	err = Run()
-	require.NoError(t, err)
-	_ = w.Close()
+	var stdout, stderr bytes.Buffer
+	args := []string{"tablo", "--complete", "--", "tablo", "-f", ";", inputFile, "Us"}
+	env := []string{"COMP_CWORD=4", "TABLO_CONFIG=/nonexistent"}
 
-	output, err := io.ReadAll(r)
+	err = RunWith(context.Background(), args, strings.NewReader(""), &stdout, &stderr, env)
 	require.NoError(t, err)
-	assert.Equal(t, "Username\n", string(output))
+	assert.Equal(t, "Username\n", stdout.String())
 }
@@ -0,0 +1,647 @@
+package tablo
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	computedSeparator     = ';'
+	computedNameSeparator = "="
+	roundBase             = 10
+)
+
+var (
+	exprFunctions = map[string][2]int{
+		"concat": {1, -1},
+		"substr": {2, 3},
+		"match":  {2, 2},
+		"upper":  {1, 1},
+		"lower":  {1, 1},
+		"trim":   {1, 1},
+		"len":    {1, 1},
+		"round":  {1, 2},
+		"bytes":  {1, 1},
+		"size":   {1, 2},
+	}
+	exprSizeSymbols = map[string][]string{
+		"decimal": decimalSizeSymbols,
+		"binary":  binarySizeSymbols,
+		"iec":     iecSizeSymbols,
+	}
+)
+
+// computedColumn is an -add-column NAME=EXPR definition.
+type computedColumn struct {
+	name string
+	expr exprNode
+}
+
+// exprNode is a node of a parsed -add-column expression. fields is the
+// input row, computed holds the values of the earlier computed columns.
+type exprNode interface {
+	eval(fields, computed []string) string
+}
+
+type exprLiteral struct{ value string }
+
+// exprColumn refers to an input column by name or, when index is not
+// negative, by zero-based index. computed is set when the name refers to an
+// earlier computed column.
+type exprColumn struct {
+	column   string
+	index    int
+	computed int
+}
+
+type exprNegate struct{ node exprNode }
+
+type exprBinary struct {
+	op          rune
+	left, right exprNode
+}
+
+type exprCall struct {
+	name string
+	args []exprNode
+	re   *regexp.Regexp
+}
+
+func (n exprLiteral) eval(_, _ []string) string { return n.value }
+
+func (n *exprColumn) eval(fields, computed []string) string {
+	if n.computed >= 0 {
+		return fieldAt(computed, n.computed)
+	}
+
+	return fieldAt(fields, n.index)
+}
+
+func (n exprNegate) eval(fields, computed []string) string {
+	v, ok := parseNumber(n.node.eval(fields, computed))
+	if !ok {
+		return ""
+	}
+
+	return formatExprNumber(-v)
+}
+
+func (n exprBinary) eval(fields, computed []string) string {
+	left, right := n.left.eval(fields, computed), n.right.eval(fields, computed)
+	if n.op == '&' {
+		return left + right
+	}
+
+	a, okA := parseNumber(left)
+	b, okB := parseNumber(right)
+	if !okA || !okB {
+		return ""
+	}
+
+	var result float64
+	switch n.op {
+	case '+':
+		result = a + b
+	case '-':
+		result = a - b
+	case '*':
+		result = a * b
+	case '/', '%':
+		if b == 0 {
+			return ""
+		}
+		result = a / b
+		if n.op == '%' {
+			result = math.Mod(a, b)
+		}
+	}
+
+	return formatExprNumber(result)
+}
+
+func (n exprCall) eval(fields, computed []string) string {
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		args[i] = arg.eval(fields, computed)
+	}
+
+	switch n.name {
+	case "concat":
+		return strings.Join(args, "")
+	case "substr":
+		return substr(args)
+	case "match":
+		m := n.re.FindStringSubmatch(args[0])
+		switch {
+		case m == nil:
+			return ""
+		case len(m) > 1:
+			return m[1]
+		default:
+			return m[0]
+		}
+	case "upper":
+		return strings.ToUpper(args[0])
+	case "lower":
+		return strings.ToLower(args[0])
+	case "trim":
+		return strings.TrimSpace(args[0])
+	case "len":
+		return strconv.Itoa(utf8.RuneCountInString(args[0]))
+	case "round":
+		return roundExprNumber(args)
+	case "bytes":
+		if size, ok := exprBytes(args[0]); ok {
+			return formatExprNumber(size)
+		}
+	case "size":
+		if size, ok := exprBytes(args[0]); ok {
+			symbols := decimalSizeSymbols
+			if len(args) > 1 {
+				symbols = exprSizeSymbols[strings.ToLower(args[1])]
+			}
+
+			return formatHumanSize(size, symbols)
+		}
+	}
+
+	return ""
+}
+
+// substr returns length runes of args[0] starting at the 1-based args[1],
+// negative starts count from the end.
+func substr(args []string) string {
+	runes := []rune(args[0])
+	start, ok := parseNumber(args[1])
+	if !ok {
+		return ""
+	}
+
+	from := int(start) - 1
+	if start < 0 {
+		from = len(runes) + int(start)
+	}
+	from = min(max(from, 0), len(runes))
+	to := len(runes)
+	if len(args) > 2 {
+		length, okLength := parseNumber(args[2])
+		if !okLength || length < 0 {
+			return ""
+		}
+		to = min(from+int(length), len(runes))
+	}
+
+	return string(runes[from:to])
+}
+
+func roundExprNumber(args []string) string {
+	n, ok := parseNumber(args[0])
+	if !ok {
+		return ""
+	}
+
+	places := 0.0
+	if len(args) > 1 {
+		if places, ok = parseNumber(args[1]); !ok {
+			return ""
+		}
+	}
+	scale := math.Pow(roundBase, math.Trunc(places))
+
+	return formatExprNumber(math.Round(n*scale) / scale)
+}
+
+// exprBytes reads a plain number or a human size such as 12.7MB as bytes.
+func exprBytes(s string) (float64, bool) {
+	if n, ok := parseNumber(s); ok {
+		return n, true
+	}
+
+	return parseHumanSize(s)
+}
+
+func formatExprNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// parseComputedColumns parses a ; separated list of NAME=EXPR definitions.
+func parseComputedColumns(spec string) ([]computedColumn, error) {
+	var columns []computedColumn
+
+	for _, item := range splitComputedSpec(spec) {
+		name, expr, found := strings.Cut(item, computedNameSeparator)
+		name = strings.TrimSpace(name)
+		if !found || name == "" || strings.TrimSpace(expr) == "" {
+			return nil, fmt.Errorf("%w, add column %q must be NAME=EXPR", ErrInvalidValue, strings.TrimSpace(item))
+		}
+
+		node, err := parseExpr(expr)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, computedColumn{name: name, expr: node})
+	}
+
+	return columns, nil
+}
+
+// splitComputedSpec splits at the separators outside quoted strings.
+func splitComputedSpec(spec string) []string {
+	var (
+		items []string
+		quote rune
+		start int
+	)
+	for i, r := range spec {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == computedSeparator:
+			items = append(items, spec[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(items, spec[start:])
+}
+
+type exprTokenKind int
+
+const (
+	exprTokenEOF exprTokenKind = iota
+	exprTokenNumber
+	exprTokenString
+	exprTokenIdent
+	exprTokenColumn
+	exprTokenIndex
+	exprTokenOperator
+	exprTokenLParen
+	exprTokenRParen
+	exprTokenComma
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	pos  int
+}
+
+type exprParser struct {
+	expr   string
+	tokens []exprToken
+	pos    int
+}
+
+func exprError(expr string, pos int, format string, args ...any) error {
+	return fmt.Errorf("%w, add column %q: %s at position %d", ErrInvalidValue, expr, fmt.Sprintf(format, args...), pos+1)
+}
+
+func isExprIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func tokenizeExpr(expr string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, exprToken{kind: exprTokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, exprToken{kind: exprTokenRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, exprToken{kind: exprTokenComma, text: ",", pos: i})
+			i++
+		case strings.ContainsRune("+-*/%&", r):
+			tokens = append(tokens, exprToken{kind: exprTokenOperator, text: string(r), pos: i})
+			i++
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == r || runes[i+1] == '\\') {
+					i++
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, exprError(expr, start, "unterminated string")
+			}
+			i++
+			tokens = append(tokens, exprToken{kind: exprTokenString, text: b.String(), pos: start})
+		case r == '$':
+			i++
+			if i < len(runes) && runes[i] == '{' {
+				end := slices.Index(runes[i:], '}')
+				if end < 0 {
+					return nil, exprError(expr, start, "unterminated column name")
+				}
+				tokens = append(tokens, exprToken{kind: exprTokenColumn, text: string(runes[i+1 : i+end]), pos: start})
+				i += end + 1
+
+				continue
+			}
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			if i == start+1 {
+				return nil, exprError(expr, start, "expected column index or {name} after \"$\"")
+			}
+			tokens = append(tokens, exprToken{kind: exprTokenIndex, text: string(runes[start+1 : i]), pos: start})
+		case unicode.IsDigit(r) || r == '.':
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: exprTokenNumber, text: string(runes[start:i]), pos: start})
+		case isExprIdentRune(r):
+			for i < len(runes) && isExprIdentRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: exprTokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			return nil, exprError(expr, start, "unexpected %q", string(r))
+		}
+	}
+
+	return append(tokens, exprToken{kind: exprTokenEOF, pos: len(runes)}), nil
+}
+
+// parseExpr parses a computed column expression such as
+// `upper(NAME) & "-" & substr(${IMAGE ID}, 1, 4)` or `size(bytes(SIZE) * 2)`.
+func parseExpr(expr string) (exprNode, error) {
+	tokens, err := tokenizeExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{expr: expr, tokens: tokens}
+	node, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != exprTokenEOF {
+		return nil, exprError(expr, tok.pos, "unexpected %q", tok.text)
+	}
+
+	return node, nil
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != exprTokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *exprParser) acceptOperator(ops string) (rune, bool) {
+	tok := p.peek()
+	if tok.kind == exprTokenOperator && strings.Contains(ops, tok.text) {
+		p.pos++
+		return rune(tok.text[0]), true
+	}
+
+	return 0, false
+}
+
+// parseBinary parses left associative operators of one precedence level.
+func (p *exprParser) parseBinary(ops string, operand func() (exprNode, error)) (exprNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.acceptOperator(ops)
+		if !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = exprBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseConcat() (exprNode, error) {
+	return p.parseBinary("&", p.parseAdditive)
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	return p.parseBinary("+-", p.parseMultiplicative)
+}
+
+func (p *exprParser) parseMultiplicative() (exprNode, error) {
+	return p.parseBinary("*/%", p.parseUnary)
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if _, ok := p.acceptOperator("-"); ok {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return exprNegate{node: node}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case exprTokenNumber:
+		if _, ok := parseNumber(tok.text); !ok {
+			return nil, exprError(p.expr, tok.pos, "invalid number %q", tok.text)
+		}
+
+		return exprLiteral{value: tok.text}, nil
+	case exprTokenString:
+		return exprLiteral{value: tok.text}, nil
+	case exprTokenIndex:
+		n, err := strconv.Atoi(tok.text)
+		if err != nil || n < 1 {
+			return nil, exprError(p.expr, tok.pos, "column index must be greater than zero")
+		}
+
+		return &exprColumn{column: "$" + tok.text, index: n - 1, computed: -1}, nil
+	case exprTokenColumn, exprTokenIdent:
+		if tok.kind == exprTokenIdent && p.peek().kind == exprTokenLParen {
+			return p.parseCall(tok)
+		}
+
+		return &exprColumn{column: tok.text, index: -1, computed: -1}, nil
+	case exprTokenLParen:
+		node, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != exprTokenRParen {
+			return nil, exprError(p.expr, closing.pos, "expected \")\"")
+		}
+
+		return node, nil
+	case exprTokenEOF:
+		return nil, exprError(p.expr, tok.pos, "unexpected end of expression")
+	default:
+		return nil, exprError(p.expr, tok.pos, "unexpected %q", tok.text)
+	}
+}
+
+func (p *exprParser) parseCall(tok exprToken) (exprNode, error) {
+	name := strings.ToLower(tok.text)
+	arity, ok := exprFunctions[name]
+	if !ok {
+		return nil, exprError(p.expr, tok.pos, "unknown function %q", tok.text)
+	}
+	p.next()
+
+	call := exprCall{name: name}
+	if p.peek().kind != exprTokenRParen {
+		for {
+			arg, err := p.parseConcat()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if p.peek().kind != exprTokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if closing := p.next(); closing.kind != exprTokenRParen {
+		return nil, exprError(p.expr, closing.pos, "expected \")\"")
+	}
+
+	if len(call.args) < arity[0] || (arity[1] >= 0 && len(call.args) > arity[1]) {
+		return nil, exprError(p.expr, tok.pos, "wrong number of arguments for %s", name)
+	}
+
+	return call, p.checkCallLiterals(tok, &call)
+}
+
+// checkCallLiterals validates the arguments that must be string literals.
+func (p *exprParser) checkCallLiterals(tok exprToken, call *exprCall) error {
+	switch call.name {
+	case "match":
+		literal, ok := call.args[1].(exprLiteral)
+		if !ok {
+			return exprError(p.expr, tok.pos, "match pattern must be a string")
+		}
+		re, err := regexp.Compile(literal.value)
+		if err != nil {
+			return exprError(p.expr, tok.pos, "invalid regex: %v", err)
+		}
+		call.re = re
+	case "size":
+		if len(call.args) < 2 {
+			return nil
+		}
+		literal, ok := call.args[1].(exprLiteral)
+		if !ok || exprSizeSymbols[strings.ToLower(literal.value)] == nil {
+			return exprError(p.expr, tok.pos, "size units must be \"decimal\", \"binary\" or \"iec\"")
+		}
+	}
+
+	return nil
+}
+
+// walkExprColumns calls fn for every column reference of node.
+func walkExprColumns(node exprNode, fn func(*exprColumn) error) error {
+	switch n := node.(type) {
+	case *exprColumn:
+		return fn(n)
+	case exprNegate:
+		return walkExprColumns(n.node, fn)
+	case exprBinary:
+		if err := walkExprColumns(n.left, fn); err != nil {
+			return err
+		}
+		return walkExprColumns(n.right, fn)
+	case exprCall:
+		for _, arg := range n.args {
+			if err := walkExprColumns(arg, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveComputedColumns binds column names to header indexes, or to the
+// computed columns defined before.
+func (t *Tablo) resolveComputedColumns(records [][]string) error {
+	if len(t.AddColumns) == 0 || len(records) == 0 {
+		return nil
+	}
+	headers := t.leadingHeaders(records)
+
+	for i, column := range t.AddColumns {
+		err := walkExprColumns(column.expr, func(ref *exprColumn) error {
+			if ref.index >= 0 || ref.computed >= 0 {
+				return nil
+			}
+			equalsName := func(name string) bool { return strings.EqualFold(name, ref.column) }
+			if ref.index = slices.IndexFunc(headers, equalsName); ref.index >= 0 {
+				return nil
+			}
+			if ref.computed = slices.IndexFunc(t.AddColumns[:i], func(c computedColumn) bool {
+				return equalsName(c.name)
+			}); ref.computed >= 0 {
+				return nil
+			}
+
+			return fmt.Errorf("%w, add column %q: column %q not found", ErrInvalidValue, column.name, ref.column)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// computedCells appends the -add-column cells of a row to its selected
+// fields. A header row gets the column names instead.
+func (t *Tablo) computedCells(selected, fields []string, header bool) []string {
+	if len(t.AddColumns) == 0 {
+		return selected
+	}
+
+	cells := slices.Clip(selected)
+	computed := make([]string, 0, len(t.AddColumns))
+	for _, column := range t.AddColumns {
+		value := column.name
+		if !header {
+			value = column.expr.eval(fields, computed)
+		}
+		computed = append(computed, value)
+	}
+
+	return append(cells, computed...)
+}
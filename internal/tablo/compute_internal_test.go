@@ -0,0 +1,93 @@
+package tablo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseComputedColumns(t *testing.T) {
+	columns, err := parseComputedColumns(`tag=REPOSITORY & ":" & TAG; short = substr(${IMAGE ID}, 1, 4)`)
+
+	require.NoError(t, err)
+	require.Len(t, columns, 2)
+	assert.Equal(t, "tag", columns[0].name)
+	assert.Equal(t, "short", columns[1].name)
+
+	for _, spec := range []string{
+		"",
+		"name",
+		"=1",
+		"x=",
+		"x=1 +",
+		"x=(1",
+		"x=nope(1)",
+		"x=upper()",
+		"x=substr(a)",
+		`x=match(a, "[")`,
+		"x=match(a, b)",
+		`x=size(a, "bits")`,
+		`x="open`,
+		"x=$0",
+		"x=${name",
+		"x=a # b",
+	} {
+		_, err = parseComputedColumns(spec)
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestComputedColumn_Eval(t *testing.T) {
+	headers := []string{"NAME", "IMAGE ID", "SIZE", "COUNT"}
+	fields := []string{"vigo/web", "911f45e85b68", "12.7MB", "4"}
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{expr: `NAME & ":" & $4`, expected: "vigo/web:4"},
+		{expr: `concat(upper(name), "-", lower("X"))`, expected: "VIGO/WEB-x"},
+		{expr: `substr(${IMAGE ID}, 1, 4)`, expected: "911f"},
+		{expr: `substr(${IMAGE ID}, -2)`, expected: "68"},
+		{expr: `match(NAME, "^([^/]+)/")`, expected: "vigo"},
+		{expr: `match(NAME, "web")`, expected: "web"},
+		{expr: `match(NAME, "^x")`, expected: ""},
+		{expr: "COUNT * 2 + 1", expected: "9"},
+		{expr: "-(COUNT - 10) % 4", expected: "2"},
+		{expr: "COUNT / 3", expected: "1.3333333333333333"},
+		{expr: "round(COUNT / 3, 2)", expected: "1.33"},
+		{expr: "COUNT / 0", expected: ""},
+		{expr: "NAME + 1", expected: ""},
+		{expr: "bytes(SIZE)", expected: "12700000"},
+		{expr: `size(bytes(SIZE) * 2)`, expected: "25.4MB"},
+		{expr: `size(2048, "iec")`, expected: "2KiB"},
+		{expr: "len(trim(\"  ab \"))", expected: "2"},
+	}
+
+	for _, tt := range tests {
+		tbl := &Tablo{FieldDelimiter: ','}
+		columns, err := parseComputedColumns("x=" + tt.expr)
+		require.NoError(t, err, tt.expr)
+		tbl.AddColumns = columns
+
+		require.NoError(t, tbl.resolveComputedColumns(tbl.splitRecords([]string{"NAME,IMAGE ID,SIZE,COUNT", "a,b,1,2"})), tt.expr)
+		assert.Equal(t, tt.expected, tbl.computedCells(nil, fields, false)[0], tt.expr)
+		assert.Equal(t, append(headers, "x"), tbl.computedCells(headers, nil, true))
+	}
+}
+
+func TestTablo_ResolveComputedColumns(t *testing.T) {
+	columns, err := parseComputedColumns("double=COUNT * 2; quad=double * 2")
+	require.NoError(t, err)
+
+	tbl := &Tablo{FieldDelimiter: ',', AddColumns: columns}
+	require.NoError(t, tbl.resolveComputedColumns(tbl.splitRecords([]string{"NAME,COUNT", "a,3"})))
+	assert.Equal(t, []string{"a", "6", "12"}, tbl.computedCells([]string{"a"}, []string{"a", "3"}, false))
+
+	columns, err = parseComputedColumns("quad=double * 2; double=COUNT * 2")
+	require.NoError(t, err)
+
+	tbl = &Tablo{FieldDelimiter: ',', AddColumns: columns}
+	err = tbl.resolveComputedColumns(tbl.splitRecords([]string{"NAME,COUNT", "a,3"}))
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
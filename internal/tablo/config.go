@@ -0,0 +1,333 @@
+package tablo
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	configEnvPrefix   = "TABLO_"
+	configEnvPath     = configEnvPrefix + "CONFIG"
+	configDirName     = "tablo"
+	configFileName    = "config"
+	configProfilesKey = "profiles"
+	configProfileKey  = "profile"
+	configColumnsKey  = "columns"
+
+	configSourceDefault = "default"
+	configSourceConfig  = "config"
+	configSourceProfile = "profile"
+	configSourceEnv     = "env"
+	configSourceFlag    = "flag"
+)
+
+// configKey maps a config key to its long and short flag names.
+type configKey struct {
+	name  string
+	short string
+}
+
+// configKeys lists the flags that can be set from the config file, a
+// profile or the environment, in -show-config order.
+var configKeys = []configKey{
+	{"field-delimiter-char", "f"},
+	{"field-delimiter-regex", "f-regex"},
+	{"line-delimiter-char", "l"},
+	{"no-separate-rows", "n"},
+	{"no-borders", "nb"},
+	{"no-headers", "nh"},
+	{"filter-indexes", "fi"},
+	{"json", "j"},
+	{"format", "fmt"},
+	{"style", "sy"},
+	{"theme", "th"},
+	{"raw-split", "rs"},
+	{"sort", "s"},
+	{"where", "w"},
+	{"stream", "st"},
+	{"stream-window", "sw"},
+	{"view", "vw"},
+	{"align", "a"},
+	{"max-width", "mw"},
+	{"width-mode", "wm"},
+	{"footer", "ft"},
+	{"group-by", "gb"},
+	{"agg", "ag"},
+	{"rename", "rn"},
+	{"add-column", "ac"},
+	{"input-format", "if"},
+	{"widths", "fw"},
+	{"color", "c"},
+	{"json-types", "jt"},
+	{"json-schema", "js"},
+	{"output", "o"},
+}
+
+// userConfig holds the config file defaults and the named profiles.
+type userConfig struct {
+	path     string
+	defaults map[string]string
+	profiles map[string]map[string]string
+}
+
+// configValue is an effective setting and where it comes from.
+type configValue struct {
+	name   string
+	value  string
+	source string
+}
+
+// configPath returns $TABLO_CONFIG, $XDG_CONFIG_HOME/tablo/config or
+// ~/.config/tablo/config.
+func configPath(getenv func(string) string) string {
+	if path := getenv(configEnvPath); path != "" {
+		return path
+	}
+	if dir := getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, configDirName, configFileName)
+	}
+	home, err := homeDir(getenv)
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".config", configDirName, configFileName)
+}
+
+// homeDir returns HOME of the environment, the user home directory when it
+// is not set.
+func homeDir(getenv func(string) string) (string, error) {
+	if home := getenv("HOME"); home != "" {
+		return home, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return home, nil
+}
+
+func configEnvName(key string) string {
+	return configEnvPrefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// loadUserConfig reads the TOML config file. A missing file is an empty
+// config.
+func loadUserConfig(path string) (*userConfig, error) {
+	cfg := &userConfig{
+		path:     path,
+		defaults: map[string]string{},
+		profiles: map[string]map[string]string{},
+	}
+	if path == "" {
+		return cfg, nil
+	}
+
+	b, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w, %w", ErrInvalidFile, err)
+	}
+
+	var raw map[string]any
+	if _, err = toml.Decode(string(b), &raw); err != nil {
+		return nil, fmt.Errorf("%w, config %s: %w", ErrInvalidValue, path, err)
+	}
+
+	for key, value := range raw {
+		if key != configProfilesKey {
+			if cfg.defaults[key], err = configString(path, key, value); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		profiles, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w, config %s: %s must be a table", ErrInvalidValue, path, key)
+		}
+		for name, settings := range profiles {
+			entries, ok := settings.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%w, config %s: profile %q must be a table", ErrInvalidValue, path, name)
+			}
+			profile := map[string]string{}
+			for k, v := range entries {
+				if profile[k], err = configString(path, k, v); err != nil {
+					return nil, err
+				}
+			}
+			cfg.profiles[name] = profile
+		}
+	}
+
+	return cfg, nil
+}
+
+// configString validates a key and converts its value to the flag string
+// representation. Lists are joined with commas.
+func configString(path, key string, value any) (string, error) {
+	known := key == configColumnsKey || slices.ContainsFunc(configKeys, func(k configKey) bool {
+		return k.name == key
+	})
+	if !known {
+		return "", fmt.Errorf("%w, config %s: unknown key %q", ErrInvalidValue, path, key)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool, int64, float64:
+		return fmt.Sprint(v), nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("%w, config %s: unsupported value for %q", ErrInvalidValue, path, key)
+	}
+}
+
+// applyUserConfig fills every flag that was not given on the command line
+// from the environment, the selected profile or the config defaults, in
+// that order, and returns the effective values with their source.
+func applyUserConfig(
+	fset *flag.FlagSet,
+	cfg *userConfig,
+	profileName string,
+	getenv func(string) string,
+) ([]configValue, error) {
+	var profile map[string]string
+	if profileName != "" {
+		var ok bool
+		if profile, ok = cfg.profiles[profileName]; !ok {
+			return nil, fmt.Errorf("%w, profile %q not found in %s", ErrInvalidValue, profileName, cfg.path)
+		}
+	}
+
+	given := map[string]bool{}
+	fset.Visit(func(f *flag.Flag) { given[f.Name] = true })
+
+	values := make([]configValue, 0, len(configKeys))
+	for _, key := range configKeys {
+		f := fset.Lookup(key.name)
+		if f == nil {
+			continue
+		}
+
+		value, source := f.Value.String(), configSourceDefault
+		switch {
+		case given[key.name] || given[key.short]:
+			source = configSourceFlag
+		case getenv(configEnvName(key.name)) != "":
+			value, source = getenv(configEnvName(key.name)), configSourceEnv+" "+configEnvName(key.name)
+		case hasKey(profile, key.name):
+			value, source = profile[key.name], configSourceProfile+" "+profileName
+		case hasKey(cfg.defaults, key.name):
+			value, source = cfg.defaults[key.name], configSourceConfig
+		}
+
+		if source != configSourceFlag && source != configSourceDefault {
+			if err := fset.Set(key.name, value); err != nil {
+				return nil, fmt.Errorf("%w, %s from %s: %w", ErrInvalidValue, key.name, source, err)
+			}
+		}
+		values = append(values, configValue{name: key.name, value: f.Value.String(), source: source})
+	}
+
+	return values, nil
+}
+
+// configColumns returns the column arguments and their source.
+func configColumns(
+	args []string,
+	cfg *userConfig,
+	profileName string,
+	getenv func(string) string,
+) ([]string, configValue) {
+	value := configValue{name: configColumnsKey, source: configSourceDefault}
+	envName := configEnvName(configColumnsKey)
+
+	var columns string
+	switch {
+	case len(args) > 0:
+		value.value, value.source = strings.Join(args, ","), configSourceFlag
+		return args, value
+	case getenv(envName) != "":
+		columns, value.source = getenv(envName), configSourceEnv+" "+envName
+	case hasKey(cfg.profiles[profileName], configColumnsKey):
+		columns, value.source = cfg.profiles[profileName][configColumnsKey], configSourceProfile+" "+profileName
+	case hasKey(cfg.defaults, configColumnsKey):
+		columns, value.source = cfg.defaults[configColumnsKey], configSourceConfig
+	default:
+		return args, value
+	}
+
+	value.value = columns
+	if columns == "" {
+		return nil, value
+	}
+
+	return strings.Split(columns, ","), value
+}
+
+// configProfileNames returns the sorted profile names of the user config.
+func configProfileNames(getenv func(string) string) []string {
+	cfg, err := loadUserConfig(configPath(getenv))
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(cfg.profiles))
+	for name := range cfg.profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func hasKey(m map[string]string, key string) bool {
+	_, ok := m[key]
+
+	return ok
+}
+
+// writeConfig prints the effective configuration for -show-config.
+func writeConfig(w io.Writer, path, profile string, values []configValue) error {
+	if profile == "" {
+		profile = "-"
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, defaultSpaceAmount, ' ', 0)
+	fmt.Fprintf(tw, "config\t%s\n", path)
+	fmt.Fprintf(tw, "profile\t%s\n\n", profile)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, v := range values {
+		fmt.Fprintf(tw, "%s\t%q\t%s\n", v.name, v.value, v.source)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package tablo
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func testConfigFlagSet() (*flag.FlagSet, *string, *bool) {
+	fset := flag.NewFlagSet("tablo", flag.ContinueOnError)
+	delimiter := fset.String("field-delimiter-char", "", "")
+	fset.StringVar(delimiter, "f", "", "")
+	noBorders := fset.Bool("no-borders", false, "")
+	fset.BoolVar(noBorders, "nb", false, "")
+	fset.String("style", defaultStyleName, "")
+
+	return fset, delimiter, noBorders
+}
+
+func testGetenv(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestConfigPath(t *testing.T) {
+	env := map[string]string{"XDG_CONFIG_HOME": "/xdg"}
+	assert.Equal(t, filepath.Join("/xdg", "tablo", "config"), configPath(testGetenv(env)))
+
+	env[configEnvPath] = "/etc/tablo.toml"
+	assert.Equal(t, "/etc/tablo.toml", configPath(testGetenv(env)))
+}
+
+func TestConfigEnvName(t *testing.T) {
+	assert.Equal(t, "TABLO_FIELD_DELIMITER_CHAR", configEnvName("field-delimiter-char"))
+}
+
+func TestLoadUserConfig(t *testing.T) {
+	path := writeTestConfig(t, `no-borders = true
+
+[profiles.passwd]
+field-delimiter-char = ":"
+filter-indexes = [1, 7]
+stream-window = 5
+columns = ["NAME", "IMAGE ID"]
+`)
+
+	cfg, err := loadUserConfig(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"no-borders": "true"}, cfg.defaults)
+	assert.Equal(t, map[string]string{
+		"field-delimiter-char": ":",
+		"filter-indexes":       "1,7",
+		"stream-window":        "5",
+		"columns":              "NAME,IMAGE ID",
+	}, cfg.profiles["passwd"])
+}
+
+func TestLoadUserConfig_MissingFile(t *testing.T) {
+	cfg, err := loadUserConfig(filepath.Join(t.TempDir(), "config"))
+
+	require.NoError(t, err)
+	assert.Empty(t, cfg.defaults)
+	assert.Empty(t, cfg.profiles)
+}
+
+func TestLoadUserConfig_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"syntax":        "no-borders = ",
+		"unknown key":   "zebra = true",
+		"profile key":   "[profiles.x]\nzebra = true\n",
+		"profiles type": "profiles = 1",
+		"profile type":  "[profiles]\nx = 1\n",
+		"value type":    "[profiles.x]\nstyle = { name = \"x\" }\n",
+	}
+
+	for name, content := range tests {
+		_, err := loadUserConfig(writeTestConfig(t, content))
+		assert.ErrorIs(t, err, ErrInvalidValue, name)
+	}
+}
+
+func TestApplyUserConfig_Precedence(t *testing.T) {
+	cfg := &userConfig{
+		defaults: map[string]string{"field-delimiter-char": ";", "no-borders": "true", "style": "bold"},
+		profiles: map[string]map[string]string{
+			"passwd": {"field-delimiter-char": ":", "style": "double"},
+		},
+	}
+	env := map[string]string{"TABLO_STYLE": "ascii"}
+
+	fset, delimiter, noBorders := testConfigFlagSet()
+	require.NoError(t, fset.Parse([]string{"-nb=false"}))
+
+	values, err := applyUserConfig(fset, cfg, "passwd", testGetenv(env))
+
+	require.NoError(t, err)
+	assert.Equal(t, ":", *delimiter)
+	assert.False(t, *noBorders)
+	assert.Equal(t, "ascii", fset.Lookup("style").Value.String())
+	assert.Equal(t, []configValue{
+		{name: "field-delimiter-char", value: ":", source: "profile passwd"},
+		{name: "no-borders", value: "false", source: "flag"},
+		{name: "style", value: "ascii", source: "env TABLO_STYLE"},
+	}, values)
+}
+
+func TestApplyUserConfig_Defaults(t *testing.T) {
+	cfg := &userConfig{defaults: map[string]string{"field-delimiter-char": ";"}}
+
+	fset, delimiter, _ := testConfigFlagSet()
+	require.NoError(t, fset.Parse(nil))
+
+	values, err := applyUserConfig(fset, cfg, "", testGetenv(nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, ";", *delimiter)
+	assert.Equal(t, "config", values[0].source)
+	assert.Equal(t, "default", values[1].source)
+}
+
+func TestApplyUserConfig_Errors(t *testing.T) {
+	cfg := &userConfig{path: "config", defaults: map[string]string{"no-borders": "maybe"}}
+
+	fset, _, _ := testConfigFlagSet()
+	require.NoError(t, fset.Parse(nil))
+
+	_, err := applyUserConfig(fset, cfg, "", testGetenv(nil))
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	_, err = applyUserConfig(fset, cfg, "missing", testGetenv(nil))
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.ErrorContains(t, err, `profile "missing" not found`)
+}
+
+func TestConfigColumns(t *testing.T) {
+	cfg := &userConfig{
+		defaults: map[string]string{"columns": "NAME"},
+		profiles: map[string]map[string]string{"docker": {"columns": "REPOSITORY,IMAGE ID"}},
+	}
+
+	args, value := configColumns([]string{"TAG"}, cfg, "docker", testGetenv(nil))
+	assert.Equal(t, []string{"TAG"}, args)
+	assert.Equal(t, "flag", value.source)
+
+	args, value = configColumns(nil, cfg, "docker", testGetenv(nil))
+	assert.Equal(t, []string{"REPOSITORY", "IMAGE ID"}, args)
+	assert.Equal(t, "profile docker", value.source)
+
+	args, value = configColumns(nil, cfg, "", testGetenv(map[string]string{"TABLO_COLUMNS": "A,B"}))
+	assert.Equal(t, []string{"A", "B"}, args)
+	assert.Equal(t, "env TABLO_COLUMNS", value.source)
+
+	args, value = configColumns(nil, cfg, "", testGetenv(nil))
+	assert.Equal(t, []string{"NAME"}, args)
+	assert.Equal(t, "config", value.source)
+}
+
+func TestWriteConfig(t *testing.T) {
+	var out bytes.Buffer
+
+	err := writeConfig(&out, "/home/vigo/.config/tablo/config", "", []configValue{
+		{name: "field-delimiter-char", value: ":", source: "profile passwd"},
+		{name: "style", value: "light", source: "default"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, `config   /home/vigo/.config/tablo/config
+profile  -
+
+KEY                   VALUE    SOURCE
+field-delimiter-char  ":"      profile passwd
+style                 "light"  default
+`, out.String())
+}
@@ -0,0 +1,155 @@
+package tablo
+
+import "io"
+
+// ColumnType is the type inferred from every non-empty value of a column.
+type ColumnType string
+
+// column types.
+const (
+	ColumnString ColumnType = "string"
+	ColumnInt    ColumnType = "int"
+	ColumnFloat  ColumnType = "float"
+	ColumnBool   ColumnType = "bool"
+)
+
+// Dataset is parsed input: the header row, the data rows and what was
+// detected while reading them. Headers is nil when the input has no header
+// row, FieldDelimiter and FieldPattern are empty for smart split input.
+type Dataset struct {
+	Headers        []string
+	Rows           [][]string
+	Types          []ColumnType
+	FieldDelimiter string
+	FieldPattern   string
+	InputFormat    InputFormat
+
+	// inlineHeader and headerRow keep how the parsed header is laid out,
+	// see jsonDataset; a headerRow header is in Headers and goes back to
+	// the first row when rendered. delimited allows -nh to drop it.
+	inlineHeader bool
+	headerRow    bool
+	delimited    bool
+}
+
+// Parse reads input and runs it through decoding, -where, -group-by,
+// -sort, column selection and computed columns. Headers and rows are the
+// ones JSON output is built from.
+func (t *Tablo) Parse(input io.Reader) (*Dataset, error) {
+	text, err := t.ReadInputFunc(input)
+	if err != nil {
+		return nil, err
+	}
+
+	t.resetInputState()
+	records, err := t.parseRecords(text)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err = t.filterRecords(records)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err = t.groupRecords(records)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err = t.sortRecords(records)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = t.resolveColumnSelection(records); err != nil {
+		return nil, err
+	}
+	if err = t.resolveComputedColumns(records); err != nil {
+		return nil, err
+	}
+
+	parsed := t.buildJSONDataset(records)
+	dataset := &Dataset{
+		Rows:         parsed.rows,
+		InputFormat:  t.inputFormat,
+		inlineHeader: parsed.inlineHeader,
+		headerRow:    parsed.headerRow,
+		delimited:    t.delimited(),
+	}
+	if parsed.hasHeader {
+		dataset.Headers = parsed.headers
+	}
+	if parsed.headerRow {
+		// -fi keeps a detected header as the first row, the dataset holds
+		// it in Headers like any other header.
+		dataset.Headers, dataset.Rows = parsed.rows[0], parsed.rows[1:]
+	}
+	if t.inputFormat == InputText {
+		dataset.FieldDelimiter = t.fieldSeparator()
+		if t.FieldPattern != nil {
+			dataset.FieldPattern = t.FieldPattern.String()
+		}
+	}
+	dataset.Types = datasetColumnTypes(dataset)
+
+	return dataset, nil
+}
+
+// Render writes the headers and rows of a dataset in the configured output
+// format. Datasets built by hand have a real header row when Headers is set.
+func (t *Tablo) Render(dataset *Dataset) error {
+	data := jsonDataset{
+		headers:      dataset.Headers,
+		rows:         dataset.Rows,
+		hasHeader:    dataset.Headers != nil,
+		inlineHeader: dataset.inlineHeader && dataset.Headers != nil,
+	}
+	if dataset.headerRow && dataset.Headers != nil {
+		data = jsonDataset{rows: append([][]string{dataset.Headers}, dataset.Rows...), headerRow: true}
+	}
+	if data.rows == nil {
+		data.rows = [][]string{}
+	}
+	if t.HideHeaders && dataset.delimited {
+		// -nh drops a detected header row instead of rendering it as data.
+		if data.headerRow {
+			data.rows, data.headerRow = data.rows[1:], false
+		}
+		data.inlineHeader = false
+	}
+
+	if t.JSONOutput {
+		return t.renderJSON(data)
+	}
+	if t.Format != "" && t.Format != FormatTable {
+		return t.renderFormat(data)
+	}
+	if t.View && IsTerminal(t.Output) {
+		return t.renderView(data)
+	}
+
+	return t.renderTable(data)
+}
+
+func datasetColumnTypes(dataset *Dataset) []ColumnType {
+	columns := len(dataset.Headers)
+	for _, row := range dataset.Rows {
+		columns = max(columns, len(row))
+	}
+
+	types := make([]ColumnType, columns)
+	for i := range types {
+		types[i] = ColumnString
+		switch inferColumnType(dataset.Rows, i) {
+		case jsonTypeInt:
+			types[i] = ColumnInt
+		case jsonTypeFloat:
+			types[i] = ColumnFloat
+		case jsonTypeBool:
+			types[i] = ColumnBool
+		}
+	}
+
+	return types
+}
@@ -0,0 +1,108 @@
+package tablo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatasetColumnTypes(t *testing.T) {
+	types := datasetColumnTypes(&Dataset{
+		Headers: []string{"name", "count", "ratio", "active"},
+		Rows: [][]string{
+			{"web", "1", "0.5", "true"},
+			{"db", "", "2", "false"},
+			{"api", "3", "1.25"},
+		},
+	})
+
+	assert.Equal(t, []ColumnType{ColumnString, ColumnInt, ColumnFloat, ColumnBool}, types)
+}
+
+func TestTablo_Parse(t *testing.T) {
+	tbl := &Tablo{ReadInputFunc: readInput, LineDelimiter: defaultLineDelimiter}
+
+	dataset, err := tbl.Parse(strings.NewReader("name,age\nvigo,42\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "age"}, dataset.Headers)
+	assert.Equal(t, [][]string{{"vigo", "42"}}, dataset.Rows)
+	assert.Equal(t, []ColumnType{ColumnString, ColumnInt}, dataset.Types)
+	assert.Equal(t, ",", dataset.FieldDelimiter)
+	assert.Equal(t, InputText, dataset.InputFormat)
+}
+
+func TestTablo_Parse_KeepsDelimiterSettings(t *testing.T) {
+	tbl := &Tablo{ReadInputFunc: readInput, LineDelimiter: defaultLineDelimiter, FieldDelimiter: ';', RawSplit: true}
+
+	dataset, err := tbl.Parse(strings.NewReader(`[{"name": "vigo", "note": "a;\"b\""}]`))
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"vigo", `a;"b"`}}, dataset.Rows)
+	assert.Equal(t, ';', tbl.FieldDelimiter)
+	assert.True(t, tbl.RawSplit)
+
+	dataset, err = tbl.Parse(strings.NewReader("id;note\n1;\"x\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "note"}, dataset.Headers)
+	assert.Equal(t, [][]string{{"1", `"x`}}, dataset.Rows)
+	assert.Equal(t, InputText, dataset.InputFormat)
+}
+
+func TestTablo_Parse_DetectsDelimiterPerInput(t *testing.T) {
+	tbl := &Tablo{ReadInputFunc: readInput, LineDelimiter: defaultLineDelimiter}
+
+	_, err := tbl.Parse(strings.NewReader("name,age\nvigo,42\n"))
+	require.NoError(t, err)
+	dataset, err := tbl.Parse(strings.NewReader("name|age\nvigo|42\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "|", dataset.FieldDelimiter)
+	assert.Equal(t, [][]string{{"vigo", "42"}}, dataset.Rows)
+	assert.Zero(t, tbl.FieldDelimiter)
+}
+
+func TestTablo_Parse_ReadError(t *testing.T) {
+	errRead := errors.New("read error")
+	tbl := &Tablo{ReadInputFunc: func(_ io.Reader) (string, error) { return "", errRead }}
+
+	_, err := tbl.Parse(strings.NewReader(""))
+
+	assert.ErrorIs(t, err, errRead)
+}
+
+func TestTablo_Render_Dataset(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{Output: nopWriteCloser{&out}, Format: FormatCSV}
+
+	err := tbl.Render(&Dataset{Rows: [][]string{{"name", "age"}, {"vigo, jr", "42"}}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "name,age\n\"vigo, jr\",42\n", out.String())
+}
+
+func TestTablo_Render_EditedDataset(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:        nopWriteCloser{&out},
+		ReadInputFunc: readInput,
+		LineDelimiter: defaultLineDelimiter,
+		Args:          []string{"name"},
+		Format:        FormatCSV,
+	}
+
+	dataset, err := tbl.Parse(strings.NewReader("name,age\nvigo,42\njohn,7\n"))
+	require.NoError(t, err)
+
+	dataset.Headers = append(dataset.Headers, "team")
+	dataset.Rows = [][]string{{"john", "core"}}
+	require.NoError(t, tbl.Render(dataset))
+
+	assert.Equal(t, "name,team\njohn,core\n", out.String())
+}
@@ -0,0 +1,161 @@
+package tablo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	fixedWidthRest = -1
+	fixedRestMark  = "*"
+)
+
+// fixedColumn is a rune range of a fixed-width line, end is fixedWidthRest
+// for a column that runs to the end of the line.
+type fixedColumn struct {
+	start int
+	end   int
+}
+
+// parseFixedWidths parses a comma separated list of column widths. A
+// trailing * keeps the rest of the line as the last column, otherwise text
+// after the last column is dropped.
+func parseFixedWidths(spec string) ([]int, error) {
+	items := strings.Split(spec, ",")
+	widths := make([]int, 0, len(items))
+
+	for i, item := range items {
+		item = strings.TrimSpace(item)
+		if item == fixedRestMark && i == len(items)-1 {
+			widths = append(widths, fixedWidthRest)
+			continue
+		}
+
+		width, err := strconv.Atoi(item)
+		if err != nil || width < 1 {
+			return nil, fmt.Errorf("%w, width %q must be a positive integer", ErrInvalidValue, item)
+		}
+		widths = append(widths, width)
+	}
+
+	return widths, nil
+}
+
+func fixedColumnsFromWidths(widths []int) []fixedColumn {
+	columns := make([]fixedColumn, 0, len(widths))
+	start := 0
+	for _, width := range widths {
+		if width == fixedWidthRest {
+			columns = append(columns, fixedColumn{start: start, end: fixedWidthRest})
+			break
+		}
+		columns = append(columns, fixedColumn{start: start, end: start + width})
+		start += width
+	}
+
+	return columns
+}
+
+func isBlankAt(line []rune, pos int) bool {
+	return pos >= len(line) || unicode.IsSpace(line[pos])
+}
+
+// fixedColumnsFromHeader infers the columns from the offsets of the header
+// words. A word starts a new column when some position of the gap before it
+// is blank in every line; the column starts right after the last such
+// position, so right aligned cells wider than their header stay whole.
+// Words separated by text in any line, such as "IMAGE ID", stay one column.
+func fixedColumnsFromHeader(lines [][]rune) []fixedColumn {
+	header := lines[0]
+	blankInAll := func(pos int) bool {
+		for _, line := range lines {
+			if !isBlankAt(line, pos) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	var starts []int
+	prevEnd := 0
+	for pos := 0; pos < len(header); pos++ {
+		if isBlankAt(header, pos) || (pos > 0 && !isBlankAt(header, pos-1)) {
+			continue
+		}
+		if len(starts) == 0 {
+			starts = append(starts, 0)
+		} else {
+			for cut := pos; cut > prevEnd; cut-- {
+				if blankInAll(cut - 1) {
+					starts = append(starts, cut)
+					break
+				}
+			}
+		}
+		prevEnd = pos
+		for !isBlankAt(header, prevEnd) {
+			prevEnd++
+		}
+	}
+
+	columns := make([]fixedColumn, len(starts))
+	for i, start := range starts {
+		end := fixedWidthRest
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		columns[i] = fixedColumn{start: start, end: end}
+	}
+
+	return columns
+}
+
+func sliceFixedColumns(line []rune, columns []fixedColumn) []string {
+	fields := make([]string, len(columns))
+	for i, column := range columns {
+		if column.start >= len(line) {
+			continue
+		}
+		end := len(line)
+		if column.end != fixedWidthRest {
+			end = min(column.end, len(line))
+		}
+		fields[i] = strings.TrimSpace(string(line[column.start:end]))
+	}
+
+	return fields
+}
+
+// fixedWidthRecords slices fixed-width text into columns, either at the
+// given -widths or at the offsets of the header line.
+func (t *Tablo) fixedWidthRecords(input string) [][]string {
+	lines := dropCommentLines(t.rawLines(input))
+	if len(lines) == 0 {
+		return nil
+	}
+
+	rows := make([][]rune, len(lines))
+	for i, line := range lines {
+		rows[i] = []rune(strings.TrimRight(line, "\r"))
+	}
+
+	columns := fixedColumnsFromWidths(t.FixedWidths)
+	if len(t.FixedWidths) == 0 {
+		columns = fixedColumnsFromHeader(rows)
+	}
+
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = sliceFixedColumns(row, columns)
+	}
+
+	t.recordInput = true
+	if len(t.FixedWidths) == 0 {
+		t.inputHeaders = records[0]
+	}
+
+	return records
+}
@@ -0,0 +1,65 @@
+package tablo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFixedWidths(t *testing.T) {
+	widths, err := parseFixedWidths("10, 8,*")
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{10, 8, fixedWidthRest}, widths)
+	assert.Equal(t, []fixedColumn{{start: 0, end: 10}, {start: 10, end: 18}, {start: 18, end: fixedWidthRest}},
+		fixedColumnsFromWidths(widths))
+
+	for _, spec := range []string{"", "10,", "0", "-1", "x", "*,10"} {
+		_, err = parseFixedWidths(spec)
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestFixedColumnsFromHeader(t *testing.T) {
+	lines := [][]rune{
+		[]rune("USER         PID %CPU STAT COMMAND"),
+		[]rune("root           1  0.0 Ss   /sbin/init splash"),
+		[]rune("vigo       12345 10.5 S+   vim main.go"),
+	}
+
+	columns := fixedColumnsFromHeader(lines)
+
+	assert.Equal(t, []fixedColumn{
+		{start: 0, end: 11},
+		{start: 11, end: 17},
+		{start: 17, end: 22},
+		{start: 22, end: 27},
+		{start: 27, end: fixedWidthRest},
+	}, columns)
+	assert.Equal(t, []string{"vigo", "12345", "10.5", "S+", "vim main.go"}, sliceFixedColumns(lines[2], columns))
+}
+
+func TestFixedColumnsFromHeader_EmptyCellsAndSpacedHeaders(t *testing.T) {
+	lines := [][]rune{
+		[]rune("IMAGE ID       STATUS       PORTS        NAMES"),
+		[]rune("4f2c1d9a8b7e   Up 2 hours   80/tcp       web"),
+		[]rune("9a8b7c6d5e4f   Up 3 days                 db"),
+	}
+
+	columns := fixedColumnsFromHeader(lines)
+
+	require.Len(t, columns, 4)
+	assert.Equal(t, []string{"IMAGE ID", "STATUS", "PORTS", "NAMES"}, sliceFixedColumns(lines[0], columns))
+	assert.Equal(t, []string{"9a8b7c6d5e4f", "Up 3 days", "", "db"}, sliceFixedColumns(lines[2], columns))
+}
+
+func TestTablo_FixedWidthRecords(t *testing.T) {
+	tbl := &Tablo{LineDelimiter: '\n', FixedWidths: []int{4, 3}}
+
+	records := tbl.fixedWidthRecords("ab  cd  ef\n# comment\ngh  ij\n")
+
+	assert.Equal(t, [][]string{{"ab", "cd"}, {"gh", "ij"}}, records)
+	assert.True(t, tbl.recordInput)
+	assert.Nil(t, tbl.inputHeaders)
+}
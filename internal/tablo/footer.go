@@ -0,0 +1,283 @@
+package tablo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Aggregate is an aggregate function of -footer and -agg.
+type Aggregate string
+
+// aggregates.
+const (
+	AggregateSum   Aggregate = "sum"
+	AggregateAvg   Aggregate = "avg"
+	AggregateMin   Aggregate = "min"
+	AggregateMax   Aggregate = "max"
+	AggregateCount Aggregate = "count"
+)
+
+const (
+	aggregateSpecSeparator = ":"
+	avgPrecision           = 100
+	sizePrecision          = 10
+)
+
+var (
+	aggregates         = []Aggregate{AggregateSum, AggregateAvg, AggregateMin, AggregateMax, AggregateCount}
+	decimalSizeSymbols = []string{"B", "kB", "MB", "GB", "TB", "PB"}
+	binarySizeSymbols  = []string{"B", "K", "M", "G", "T", "P"}
+	iecSizeSymbols     = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+)
+
+// aggregateField aggregates a column given by header name or, when index
+// is not negative, by zero-based output column index.
+type aggregateField struct {
+	column    string
+	index     int
+	aggregate Aggregate
+}
+
+// parseAggregates parses a comma separated list of COLUMN:AGGREGATE entries
+// where COLUMN is a header name or a 1-based column index. kind names the
+// flag in error messages.
+func parseAggregates(kind, spec string) ([]aggregateField, error) {
+	var fields []aggregateField
+
+	for item := range strings.SplitSeq(spec, ",") {
+		item = strings.TrimSpace(item)
+		idx := strings.LastIndex(item, aggregateSpecSeparator)
+		if idx <= 0 {
+			return nil, fmt.Errorf("%w, %s entry %q must be COLUMN:AGGREGATE", ErrInvalidValue, kind, item)
+		}
+
+		column, name := item[:idx], item[idx+1:]
+		aggregate := Aggregate(strings.ToLower(name))
+		if !slices.Contains(aggregates, aggregate) {
+			return nil, fmt.Errorf("%w, %s entry %q has unknown aggregate %q", ErrInvalidValue, kind, item, name)
+		}
+
+		field := aggregateField{column: column, index: -1, aggregate: aggregate}
+		if n, err := strconv.Atoi(column); err == nil {
+			if n < 1 {
+				return nil, fmt.Errorf("%w, %s column index %d must be greater than zero", ErrInvalidValue, kind, n)
+			}
+			field.index = n - 1
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// columnIndex returns the zero-based index of the field column, -1 when
+// the header is not found.
+func (f aggregateField) columnIndex(headers []string) int {
+	if f.index >= 0 {
+		return f.index
+	}
+
+	return slices.IndexFunc(headers, func(header string) bool {
+		return strings.EqualFold(header, f.column)
+	})
+}
+
+// footerValue is a parsed cell, sizes remember the unit symbols of the
+// column so the result is printed the same way.
+type footerValue struct {
+	cell    string
+	number  float64
+	symbols []string
+}
+
+func parseFooterValue(cell string) (footerValue, bool) {
+	cell = strings.TrimSpace(cell)
+	if n, ok := parseNumber(cell); ok {
+		return footerValue{cell: cell, number: n}, true
+	}
+
+	n, ok := parseHumanSize(cell)
+	if !ok {
+		return footerValue{}, false
+	}
+
+	_, unit := splitNumberPrefix(cell)
+	unit = strings.ToLower(unit)
+	symbols := decimalSizeSymbols
+	switch {
+	case len(unit) == 1 && unit != "b":
+		symbols = binarySizeSymbols
+	case strings.HasSuffix(unit, "ib"):
+		symbols = iecSizeSymbols
+	}
+
+	return footerValue{cell: cell, number: n, symbols: symbols}, true
+}
+
+// formatHumanSize prints a size in bytes with the largest unit that keeps
+// the value above one, rounded to one decimal.
+func formatHumanSize(size float64, symbols []string) string {
+	base := float64(sizeKilo)
+	if symbols[1] != decimalSizeSymbols[1] {
+		base = sizeKibi
+	}
+
+	unit := 0
+	for math.Abs(size) >= base && unit < len(symbols)-1 {
+		size /= base
+		unit++
+	}
+
+	return strconv.FormatFloat(math.Round(size*sizePrecision)/sizePrecision, 'f', -1, 64) + symbols[unit]
+}
+
+// aggregateColumn computes an aggregate over the non-empty cells of a
+// column. Cells that are not numbers or sizes are skipped, min and max
+// keep the original cell.
+func aggregateColumn(rows [][]string, column int, aggregate Aggregate) string {
+	var values []footerValue
+	count := 0
+	for _, row := range rows {
+		cell := fieldAt(row, column)
+		if strings.TrimSpace(cell) == "" {
+			continue
+		}
+		count++
+		if value, ok := parseFooterValue(cell); ok {
+			values = append(values, value)
+		}
+	}
+
+	if aggregate == AggregateCount {
+		return strconv.Itoa(count)
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	var (
+		result  float64
+		symbols []string
+	)
+	switch aggregate {
+	case AggregateMin, AggregateMax:
+		cmp := func(a, b footerValue) int {
+			switch {
+			case a.number < b.number:
+				return -1
+			case a.number > b.number:
+				return 1
+			default:
+				return 0
+			}
+		}
+		if aggregate == AggregateMin {
+			return slices.MinFunc(values, cmp).cell
+		}
+
+		return slices.MaxFunc(values, cmp).cell
+	default:
+		for _, value := range values {
+			result += value.number
+			if value.symbols != nil {
+				symbols = value.symbols
+			}
+		}
+		if aggregate == AggregateAvg {
+			result /= float64(len(values))
+		}
+	}
+
+	if symbols != nil {
+		return formatHumanSize(result, symbols)
+	}
+	if aggregate == AggregateAvg {
+		result = math.Round(result*avgPrecision) / avgPrecision
+	}
+
+	return strconv.FormatFloat(result, 'f', -1, 64)
+}
+
+// footerRow returns the footer cells of every output column and the
+// aggregated column indexes in -footer order, nil when no footer is
+// requested.
+func (t *Tablo) footerRow(headers []string, rows [][]string) ([]string, []int, error) {
+	if len(t.Footer) == 0 {
+		return nil, nil, nil
+	}
+
+	columns := len(headers)
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	footer := make([]string, columns)
+	indexes := make([]int, 0, len(t.Footer))
+	for _, field := range t.Footer {
+		idx := field.columnIndex(headers)
+		if idx < 0 || idx >= columns {
+			return nil, nil, fmt.Errorf("%w, footer column %q not found", ErrInvalidValue, field.column)
+		}
+		if slices.Contains(indexes, idx) {
+			return nil, nil, fmt.Errorf("%w, footer column %q is aggregated twice", ErrInvalidValue, field.column)
+		}
+		indexes = append(indexes, idx)
+		footer[idx] = aggregateColumn(rows, idx, field.aggregate)
+	}
+
+	return footer, indexes, nil
+}
+
+// jsonFooter encodes the aggregates as an object keyed by header name or
+// 1-based column index. Typed output emits numbers.
+func jsonFooter(dataset jsonDataset, footer []string, indexes []int, typed bool) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, idx := range indexes {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		key := strconv.Itoa(idx + 1)
+		if dataset.hasHeader && idx < len(dataset.headers) {
+			key = dataset.headers[idx]
+		}
+		if err := writeJSONString(&buf, key); err != nil {
+			return nil, err
+		}
+		buf.WriteString(":")
+
+		switch value := footer[idx]; {
+		case typed && jsonNumberPattern.MatchString(value):
+			buf.WriteString(value)
+		case typed && value == "":
+			buf.WriteString("null")
+		default:
+			if err := writeJSONString(&buf, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	buf.WriteString("}")
+
+	return buf.Bytes(), nil
+}
+
+// jsonWithFooter wraps the row array in a {"rows": [...], "_footer": {...}}
+// object, so every element of the row array keeps the same shape.
+func jsonWithFooter(rows, footer json.RawMessage) ([]byte, error) {
+	b, err := json.MarshalIndent(struct {
+		Rows   json.RawMessage `json:"rows"`
+		Footer json.RawMessage `json:"_footer"`
+	}{rows, footer}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,83 @@
+package tablo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAggregates(t *testing.T) {
+	fields, err := parseAggregates("footer", "SIZE:sum, image id:COUNT,2:avg")
+
+	require.NoError(t, err)
+	assert.Equal(t, []aggregateField{
+		{column: "SIZE", index: -1, aggregate: AggregateSum},
+		{column: "image id", index: -1, aggregate: AggregateCount},
+		{column: "2", index: 1, aggregate: AggregateAvg},
+	}, fields)
+
+	for _, spec := range []string{"SIZE", ":sum", "SIZE:total", "0:sum", "SIZE:sum,"} {
+		_, err = parseAggregates("footer", spec)
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestFormatHumanSize(t *testing.T) {
+	assert.Equal(t, "1.2GB", formatHumanSize(1_213_500_000, decimalSizeSymbols))
+	assert.Equal(t, "512B", formatHumanSize(512, decimalSizeSymbols))
+	assert.Equal(t, "1.5K", formatHumanSize(1536, binarySizeSymbols))
+	assert.Equal(t, "2MiB", formatHumanSize(2*sizeKibi*sizeKibi, iecSizeSymbols))
+}
+
+func TestAggregateColumn(t *testing.T) {
+	rows := [][]string{
+		{"web", "12.7MB", "3"},
+		{"db", "1.2GB", "7"},
+		{"cache", "<none>", "2"},
+		{"tmp", "", "x"},
+	}
+
+	tests := []struct {
+		column    int
+		aggregate Aggregate
+		want      string
+	}{
+		{0, AggregateCount, "4"},
+		{1, AggregateCount, "3"},
+		{1, AggregateSum, "1.2GB"},
+		{1, AggregateMin, "12.7MB"},
+		{1, AggregateMax, "1.2GB"},
+		{2, AggregateSum, "12"},
+		{2, AggregateAvg, "4"},
+		{2, AggregateMax, "7"},
+		{0, AggregateSum, ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, aggregateColumn(rows, tt.column, tt.aggregate), tt)
+	}
+
+	assert.Equal(t, "0.33", aggregateColumn([][]string{{"1"}, {"0"}, {"0"}}, 0, AggregateAvg))
+}
+
+func TestTablo_FooterRow(t *testing.T) {
+	tbl := &Tablo{Footer: []aggregateField{
+		{column: "size", index: -1, aggregate: AggregateSum},
+		{column: "1", index: 0, aggregate: AggregateCount},
+	}}
+
+	footer, indexes, err := tbl.footerRow([]string{"NAME", "SIZE"}, [][]string{{"a", "1"}, {"b", "2"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, footer)
+	assert.Equal(t, []int{1, 0}, indexes)
+
+	tbl.Footer = append(tbl.Footer, aggregateField{column: "NAME", index: -1, aggregate: AggregateMax})
+	_, _, err = tbl.footerRow([]string{"NAME", "SIZE"}, nil)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	tbl.Footer = []aggregateField{{column: "AGE", index: -1, aggregate: AggregateSum}}
+	_, _, err = tbl.footerRow([]string{"NAME", "SIZE"}, nil)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
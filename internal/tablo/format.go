@@ -0,0 +1,262 @@
+package tablo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// nulRecordTerminator ends csv and tsv records instead of a newline when the
+// input lines are NUL separated, so the output can be piped into xargs -0.
+const nulRecordTerminator = "\x00"
+
+// OutputFormat defines how the table is rendered.
+type OutputFormat string
+
+// output formats.
+const (
+	FormatTable    OutputFormat = "table"
+	FormatJSON     OutputFormat = "json"
+	FormatCSV      OutputFormat = "csv"
+	FormatTSV      OutputFormat = "tsv"
+	FormatMarkdown OutputFormat = "markdown"
+	FormatHTML     OutputFormat = "html"
+	FormatLaTeX    OutputFormat = "latex"
+)
+
+var (
+	outputFormats = []OutputFormat{
+		FormatTable,
+		FormatJSON,
+		FormatCSV,
+		FormatTSV,
+		FormatMarkdown,
+		FormatHTML,
+		FormatLaTeX,
+	}
+	outputFormatAliases = map[string]OutputFormat{
+		"box": FormatTable,
+		"md":  FormatMarkdown,
+		"tex": FormatLaTeX,
+	}
+	latexReplacer = strings.NewReplacer(
+		`\`, `\textbackslash{}`,
+		`&`, `\&`,
+		`%`, `\%`,
+		`$`, `\$`,
+		`#`, `\#`,
+		`_`, `\_`,
+		`{`, `\{`,
+		`}`, `\}`,
+		`~`, `\textasciitilde{}`,
+		`^`, `\textasciicircum{}`,
+		"\n", `\newline `,
+	)
+)
+
+func parseOutputFormat(s string) (OutputFormat, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if format, ok := outputFormatAliases[name]; ok {
+		return format, nil
+	}
+	for _, format := range outputFormats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w, unknown output format %q", ErrInvalidValue, s)
+}
+
+func outputFormatNames() []string {
+	names := make([]string, 0, len(outputFormats))
+	for _, format := range outputFormats {
+		names = append(names, string(format))
+	}
+
+	return names
+}
+
+// renderFormat renders the dataset with one of the document formats. Unlike
+// the box table the detected header row is always emitted as a real header
+// because csv, markdown, html and latex readers rely on it.
+func (t *Tablo) renderFormat(dataset jsonDataset) error {
+	if dataset.headerRow {
+		// -fi keeps a detected header row as data, promote it back.
+		dataset.headers = dataset.rows[0]
+		dataset.rows = dataset.rows[1:]
+		dataset.hasHeader = true
+	}
+
+	footer, _, err := t.footerRow(dataset.headers, dataset.rows)
+	if err != nil {
+		return err
+	}
+
+	headers := dataset.headers
+	if dataset.hasHeader {
+		if dataset.headers, err = t.renameHeaders(headers); err != nil {
+			return err
+		}
+	}
+
+	switch t.Format {
+	case FormatLaTeX:
+		return t.renderLaTeX(dataset, footer)
+	case FormatCSV:
+		if t.nulRecords() {
+			return t.writeNULRecords(dataset, footer, ',')
+		}
+
+		return t.renderCSV(dataset, footer)
+	case FormatTSV:
+		if t.nulRecords() {
+			return t.writeNULRecords(dataset, footer, '\t')
+		}
+	}
+
+	columnConfigs, err := t.columnConfigs(headers, dataset.rows, nil)
+	if err != nil {
+		return err
+	}
+
+	tw := table.NewWriter()
+	tw.SetOutputMirror(t.Output)
+	tw.SetColumnConfigs(columnConfigs)
+	if dataset.hasHeader && !t.HideHeaders {
+		tw.AppendHeader(stringSliceToRow(dataset.headers))
+	}
+	for _, row := range dataset.rows {
+		tw.AppendRow(stringSliceToRow(row))
+	}
+	if footer != nil {
+		tw.AppendFooter(stringSliceToRow(footer))
+	}
+
+	switch t.Format {
+	case FormatTSV:
+		tw.RenderTSV()
+	case FormatMarkdown:
+		tw.RenderMarkdown()
+	case FormatHTML:
+		tw.RenderHTML()
+	}
+
+	return nil
+}
+
+// renderCSV uses encoding/csv instead of go-pretty's RenderCSV, which
+// escapes commas with a backslash and does not produce RFC 4180 output.
+func (t *Tablo) renderCSV(dataset jsonDataset, footer []string) error {
+	w := csv.NewWriter(t.Output)
+	if dataset.hasHeader && !t.HideHeaders {
+		if err := w.Write(dataset.headers); err != nil {
+			return fmt.Errorf(errorWrapFormat, err)
+		}
+	}
+	if err := w.WriteAll(dataset.rows); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+	if footer != nil {
+		if err := w.WriteAll([][]string{footer}); err != nil {
+			return fmt.Errorf(errorWrapFormat, err)
+		}
+	}
+
+	return nil
+}
+
+// nulRecords reports whether csv and tsv records end with NUL.
+func (t *Tablo) nulRecords() bool {
+	return t.LineSeparator == nulRecordTerminator
+}
+
+// writeNULRecords writes csv or tsv records terminated by NUL. Fields keep
+// the RFC 4180 quoting so records with several columns split back into
+// their fields; a single column is written unquoted since a cell can not
+// hold NUL, and the records can go straight to xargs -0.
+func (t *Tablo) writeNULRecords(dataset jsonDataset, footer []string, fieldDelimiter rune) error {
+	records := make([][]string, 0, len(dataset.rows)+2)
+	if dataset.hasHeader && !t.HideHeaders {
+		records = append(records, dataset.headers)
+	}
+	records = append(records, dataset.rows...)
+	if footer != nil {
+		records = append(records, footer)
+	}
+
+	quote := false
+	for _, record := range records {
+		quote = quote || len(record) > 1
+	}
+
+	var b, record strings.Builder
+	w := csv.NewWriter(&record)
+	w.Comma = fieldDelimiter
+	for _, fields := range records {
+		if !quote {
+			b.WriteString(fieldAt(fields, 0))
+			b.WriteString(nulRecordTerminator)
+
+			continue
+		}
+
+		record.Reset()
+		if err := w.Write(fields); err != nil {
+			return fmt.Errorf(errorWrapFormat, err)
+		}
+		w.Flush()
+		b.WriteString(strings.TrimSuffix(record.String(), "\n"))
+		b.WriteString(nulRecordTerminator)
+	}
+
+	if _, err := io.WriteString(t.Output, b.String()); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return nil
+}
+
+func (t *Tablo) renderLaTeX(dataset jsonDataset, footer []string) error {
+	columns := len(dataset.headers)
+	for _, row := range dataset.rows {
+		columns = max(columns, len(row))
+	}
+
+	var b strings.Builder
+	writeRow := func(fields []string) {
+		cells := make([]string, columns)
+		for i := range cells {
+			cells[i] = latexReplacer.Replace(fieldAt(fields, i))
+		}
+		b.WriteString(strings.Join(cells, " & "))
+		b.WriteString(" \\\\\n")
+	}
+
+	b.WriteString("\\begin{tabular}{|" + strings.Repeat("l|", columns) + "}\n")
+	b.WriteString("\\hline\n")
+	if dataset.hasHeader && !t.HideHeaders {
+		writeRow(dataset.headers)
+		b.WriteString("\\hline\n")
+	}
+	for _, row := range dataset.rows {
+		writeRow(row)
+	}
+	if len(dataset.rows) > 0 {
+		b.WriteString("\\hline\n")
+	}
+	if footer != nil {
+		writeRow(footer)
+		b.WriteString("\\hline\n")
+	}
+	b.WriteString("\\end{tabular}\n")
+
+	if _, err := io.WriteString(t.Output, b.String()); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return nil
+}
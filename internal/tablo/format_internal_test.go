@@ -0,0 +1,148 @@
+package tablo
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := map[string]OutputFormat{
+		"table":    FormatTable,
+		"box":      FormatTable,
+		"JSON":     FormatJSON,
+		"csv":      FormatCSV,
+		"tsv":      FormatTSV,
+		"md":       FormatMarkdown,
+		"markdown": FormatMarkdown,
+		"html":     FormatHTML,
+		" tex ":    FormatLaTeX,
+		"latex":    FormatLaTeX,
+	}
+
+	for in, want := range tests {
+		got, err := parseOutputFormat(in)
+
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := parseOutputFormat("xml")
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestTablo_RenderFormat(t *testing.T) {
+	lines := []string{"name|note", "vigo|a, b", "john|x"}
+
+	tests := []struct {
+		format OutputFormat
+		want   string
+	}{
+		{FormatCSV, "name,note\nvigo,\"a, b\"\njohn,x\n"},
+		{FormatTSV, "name\tnote\nvigo\ta, b\njohn\tx\n"},
+		{FormatMarkdown, "| name | note |\n| --- | --- |\n| vigo | a, b |\n| john | x |\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var out bytes.Buffer
+			tbl := &Tablo{
+				Output:         nopWriteCloser{&out},
+				FieldDelimiter: '|',
+				Format:         tt.format,
+			}
+
+			require.NoError(t, tbl.renderFormat(tbl.buildJSONDataset(tbl.splitRecords(lines))))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestTablo_RenderFormat_NULRecords(t *testing.T) {
+	lines := []string{"name|note", "vigo|a\nb", "./a,b|say \"hi\""}
+
+	tests := []struct {
+		format OutputFormat
+		want   string
+	}{
+		{FormatCSV, "name,note\x00vigo,\"a\nb\"\x00\"./a,b\",\"say \"\"hi\"\"\"\x00"},
+		{FormatTSV, "name\tnote\x00vigo\t\"a\nb\"\x00./a,b\t\"say \"\"hi\"\"\"\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var out bytes.Buffer
+			tbl := &Tablo{
+				Output:         nopWriteCloser{&out},
+				LineSeparator:  "\x00",
+				FieldDelimiter: '|',
+				Format:         tt.format,
+			}
+
+			require.NoError(t, tbl.renderFormat(tbl.buildJSONDataset(tbl.splitRecords(lines))))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestTablo_RenderFormat_HTML(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		FieldDelimiter: '|',
+		Format:         FormatHTML,
+		Args:           []string{"name"},
+	}
+
+	require.NoError(t, tbl.renderFormat(tbl.buildJSONDataset(tbl.splitRecords([]string{"name|age", "<b>vigo</b>|42"}))))
+	assert.Contains(t, out.String(), "<th>name</th>")
+	assert.NotContains(t, out.String(), "<th>age</th>")
+	assert.Contains(t, out.String(), "<td>&lt;b&gt;vigo&lt;/b&gt;</td>")
+}
+
+func TestTablo_RenderFormat_LaTeX(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		FieldDelimiter: '|',
+		Format:         FormatLaTeX,
+	}
+
+	require.NoError(t, tbl.renderFormat(tbl.buildJSONDataset(tbl.splitRecords([]string{"name|cost", "a_b|50% & $1", "c"}))))
+	assert.Equal(t, `\begin{tabular}{|l|l|}
+\hline
+name & cost \\
+\hline
+a\_b & 50\% \& \$1 \\
+c &  \\
+\hline
+\end{tabular}
+`, out.String())
+}
+
+func TestTablo_RenderFormat_LaTeX_WithoutHeader(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		FieldDelimiter: ':',
+		FilterIndexes:  []int{0},
+		Format:         FormatLaTeX,
+	}
+
+	require.NoError(t, tbl.renderFormat(tbl.buildJSONDataset(tbl.splitRecords([]string{"root:0"}))))
+	assert.Equal(t, "\\begin{tabular}{|l|}\n\\hline\nroot \\\\\n\\hline\n\\end{tabular}\n", out.String())
+}
+
+func TestTablo_RenderFormat_LaTeX_ReturnsWriteError(t *testing.T) {
+	writeErr := errors.New("write failed")
+	tbl := &Tablo{
+		Output:         &errorWriteCloser{err: writeErr},
+		FieldDelimiter: '|',
+		Format:         FormatLaTeX,
+	}
+
+	assert.ErrorIs(t, tbl.renderFormat(tbl.buildJSONDataset(tbl.splitRecords([]string{"a|b"}))), writeErr)
+}
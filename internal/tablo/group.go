@@ -0,0 +1,143 @@
+package tablo
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const groupKeySeparator = "\x00"
+
+// groupKey is a group-by column given by header name or, when index is not
+// negative, by zero-based column index.
+type groupKey struct {
+	column string
+	index  int
+}
+
+// parseGroupBy parses a comma separated list of header names or 1-based
+// column indexes.
+func parseGroupBy(spec string) ([]groupKey, error) {
+	var keys []groupKey
+
+	for column := range strings.SplitSeq(spec, ",") {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			return nil, fmt.Errorf("%w, empty group by column in %q", ErrInvalidValue, spec)
+		}
+
+		key := groupKey{column: column, index: -1}
+		if n, err := strconv.Atoi(column); err == nil {
+			if n < 1 {
+				return nil, fmt.Errorf("%w, group by column index %d must be greater than zero", ErrInvalidValue, n)
+			}
+			key.index = n - 1
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// groupColumnIndices resolves the group-by columns against the header row
+// the same way column arguments are matched.
+func (t *Tablo) groupColumnIndices(headers []string, columns int) ([]int, error) {
+	indices := make([]int, 0, len(t.GroupBy))
+	for _, key := range t.GroupBy {
+		idx := key.index
+		if idx < 0 {
+			idx = slices.IndexFunc(headers, func(header string) bool {
+				return strings.EqualFold(header, key.column)
+			})
+		}
+		if idx < 0 || idx >= columns {
+			return nil, fmt.Errorf("%w, group by column %q not found", ErrInvalidValue, key.column)
+		}
+		indices = append(indices, idx)
+	}
+
+	return indices, nil
+}
+
+// groupRecords collapses the data records into one record per distinct
+// group key in order of first appearance, followed by the -agg aggregates.
+// Aggregate columns are named AGGREGATE(COLUMN). Like decoded structured
+// input, the result always leads with its header row.
+func (t *Tablo) groupRecords(records [][]string) ([][]string, error) {
+	if len(t.GroupBy) == 0 {
+		if len(t.Aggregates) > 0 {
+			return nil, fmt.Errorf("%w, agg requires group by", ErrInvalidValue)
+		}
+
+		return records, nil
+	}
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	headers := t.leadingHeaders(records)
+	start := 0
+	if headers != nil {
+		start = 1
+	}
+
+	rows := records[start:]
+	columns := len(headers)
+	for _, fields := range rows {
+		columns = max(columns, len(fields))
+	}
+
+	keyIndices, err := t.groupColumnIndices(headers, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	columnName := func(idx int) string {
+		if idx < len(headers) {
+			return headers[idx]
+		}
+
+		return strconv.Itoa(idx + 1)
+	}
+
+	groupHeaders := make([]string, 0, len(keyIndices)+len(t.Aggregates))
+	for _, idx := range keyIndices {
+		groupHeaders = append(groupHeaders, columnName(idx))
+	}
+	aggregateIndices := make([]int, 0, len(t.Aggregates))
+	for _, field := range t.Aggregates {
+		idx := field.columnIndex(headers)
+		if idx < 0 || idx >= columns {
+			return nil, fmt.Errorf("%w, agg column %q not found", ErrInvalidValue, field.column)
+		}
+		aggregateIndices = append(aggregateIndices, idx)
+		groupHeaders = append(groupHeaders, fmt.Sprintf("%s(%s)", field.aggregate, columnName(idx)))
+	}
+
+	var order []string
+	groups := map[string][][]string{}
+	for _, row := range rows {
+		key := strings.Join(pickFieldsByIndices(row, keyIndices), groupKeySeparator)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+
+	t.recordInput = true
+	t.inputHeaders = groupHeaders
+
+	grouped := make([][]string, 0, len(order)+1)
+	grouped = append(grouped, groupHeaders)
+	for _, key := range order {
+		members := groups[key]
+		fields := pickFieldsByIndices(members[0], keyIndices)
+		for i, field := range t.Aggregates {
+			fields = append(fields, aggregateColumn(members, aggregateIndices[i], field.aggregate))
+		}
+		grouped = append(grouped, fields)
+	}
+
+	return grouped, nil
+}
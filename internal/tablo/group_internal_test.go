@@ -0,0 +1,81 @@
+package tablo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	keys, err := parseGroupBy("REPOSITORY, 2")
+
+	require.NoError(t, err)
+	assert.Equal(t, []groupKey{
+		{column: "REPOSITORY", index: -1},
+		{column: "2", index: 1},
+	}, keys)
+
+	for _, spec := range []string{"", "NAME,", "0"} {
+		_, err = parseGroupBy(spec)
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestTablo_GroupRecords(t *testing.T) {
+	aggregates, err := parseAggregates("agg", "size:sum,tag:count")
+	require.NoError(t, err)
+
+	tbl := &Tablo{
+		FieldDelimiter: '|',
+		GroupBy:        []groupKey{{column: "repository", index: -1}},
+		Aggregates:     aggregates,
+	}
+	records, err := tbl.groupRecords(tbl.splitRecords([]string{
+		"REPOSITORY|TAG|SIZE",
+		"web|latest|12.7MB",
+		"db|15|1.2GB",
+		"web|1.0|10MB",
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"REPOSITORY", "sum(SIZE)", "count(TAG)"},
+		{"web", "22.7MB", "2"},
+		{"db", "1.2GB", "1"},
+	}, records)
+	assert.Equal(t, []string{"REPOSITORY", "sum(SIZE)", "count(TAG)"}, tbl.inputHeaders)
+	assert.Equal(t, '|', tbl.FieldDelimiter)
+}
+
+func TestTablo_GroupRecords_WithoutHeader(t *testing.T) {
+	tbl := &Tablo{
+		FieldDelimiter: ',',
+		GroupBy:        []groupKey{{column: "1", index: 0}},
+		Aggregates:     []aggregateField{{column: "2", index: 1, aggregate: AggregateMax}},
+	}
+	records, err := tbl.groupRecords([][]string{{"a", "1"}, {"b", "5"}, {"a", "3"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"1", "max(2)"}, {"a", "3"}, {"b", "5"}}, records)
+}
+
+func TestTablo_GroupRecords_Errors(t *testing.T) {
+	records := [][]string{{"NAME", "SIZE"}, {"web", "1"}}
+
+	tbl := &Tablo{FieldDelimiter: '|', Aggregates: []aggregateField{{column: "SIZE", index: -1}}}
+	_, err := tbl.groupRecords(records)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	tbl = &Tablo{FieldDelimiter: '|', GroupBy: []groupKey{{column: "AGE", index: -1}}}
+	_, err = tbl.groupRecords(records)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	tbl = &Tablo{
+		FieldDelimiter: '|',
+		GroupBy:        []groupKey{{column: "NAME", index: -1}},
+		Aggregates:     []aggregateField{{column: "AGE", index: -1, aggregate: AggregateSum}},
+	}
+	_, err = tbl.groupRecords(records)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
@@ -0,0 +1,154 @@
+package tablo
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	sizeKilo   = 1000
+	sizeKibi   = 1024
+	hoursInDay = 24
+	daysInWeek = 7
+	daysInYear = 365
+	daysInMon  = 30
+)
+
+var (
+	// decimal units follow docker/SI, binary and single letter units follow
+	// ls -h which reports powers of 1024.
+	sizeUnits = map[string]float64{
+		"b":   1,
+		"kb":  sizeKilo,
+		"mb":  sizeKilo * sizeKilo,
+		"gb":  sizeKilo * sizeKilo * sizeKilo,
+		"tb":  sizeKilo * sizeKilo * sizeKilo * sizeKilo,
+		"pb":  sizeKilo * sizeKilo * sizeKilo * sizeKilo * sizeKilo,
+		"k":   sizeKibi,
+		"m":   sizeKibi * sizeKibi,
+		"g":   sizeKibi * sizeKibi * sizeKibi,
+		"t":   sizeKibi * sizeKibi * sizeKibi * sizeKibi,
+		"p":   sizeKibi * sizeKibi * sizeKibi * sizeKibi * sizeKibi,
+		"kib": sizeKibi,
+		"mib": sizeKibi * sizeKibi,
+		"gib": sizeKibi * sizeKibi * sizeKibi,
+		"tib": sizeKibi * sizeKibi * sizeKibi * sizeKibi,
+		"pib": sizeKibi * sizeKibi * sizeKibi * sizeKibi * sizeKibi,
+	}
+
+	durationUnits = map[string]time.Duration{
+		"s":       time.Second,
+		"sec":     time.Second,
+		"second":  time.Second,
+		"seconds": time.Second,
+		"m":       time.Minute,
+		"min":     time.Minute,
+		"minute":  time.Minute,
+		"minutes": time.Minute,
+		"h":       time.Hour,
+		"hour":    time.Hour,
+		"hours":   time.Hour,
+		"d":       hoursInDay * time.Hour,
+		"day":     hoursInDay * time.Hour,
+		"days":    hoursInDay * time.Hour,
+		"w":       daysInWeek * hoursInDay * time.Hour,
+		"week":    daysInWeek * hoursInDay * time.Hour,
+		"weeks":   daysInWeek * hoursInDay * time.Hour,
+		"month":   daysInMon * hoursInDay * time.Hour,
+		"months":  daysInMon * hoursInDay * time.Hour,
+		"y":       daysInYear * hoursInDay * time.Hour,
+		"year":    daysInYear * hoursInDay * time.Hour,
+		"years":   daysInYear * hoursInDay * time.Hour,
+	}
+)
+
+// splitNumberPrefix splits s into its leading decimal number and the rest.
+func splitNumberPrefix(s string) (string, string) {
+	end := 0
+	for i, r := range s {
+		if unicode.IsDigit(r) || r == '.' || (i == 0 && (r == '-' || r == '+')) {
+			end = i + 1
+			continue
+		}
+
+		break
+	}
+
+	return s[:end], strings.TrimSpace(s[end:])
+}
+
+// parseNumber parses a plain decimal number.
+func parseNumber(s string) (float64, bool) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+
+	return n, err == nil
+}
+
+// parseHumanSize parses sizes such as "512", "12.7MB", "4.0K" or "1GiB" and
+// returns the value in bytes.
+func parseHumanSize(s string) (float64, bool) {
+	number, unit := splitNumberPrefix(strings.TrimSpace(s))
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, false
+	}
+	if unit == "" {
+		return n, true
+	}
+
+	multiplier, ok := sizeUnits[strings.ToLower(unit)]
+	if !ok {
+		return 0, false
+	}
+
+	return n * multiplier, true
+}
+
+// isSizeLiteral reports whether s is an explicit size such as "10mb" or
+// "1G". Lower case single letters are left to durations ("10m").
+func isSizeLiteral(s string) bool {
+	number, unit := splitNumberPrefix(s)
+	if number == "" || unit == "" {
+		return false
+	}
+	if len(unit) == 1 && unicode.IsLower(rune(unit[0])) && unit != "b" {
+		return false
+	}
+	_, ok := sizeUnits[strings.ToLower(unit)]
+
+	return ok
+}
+
+// parseHumanDuration parses Go durations ("1h30m"), compact day/week forms
+// ("3d", "2w") and relative phrases such as "22 hours ago" or "About an
+// hour ago" as printed by docker.
+func parseHumanDuration(s string) (time.Duration, bool) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, true
+	}
+
+	s = strings.TrimSuffix(s, " ago")
+	s = strings.TrimPrefix(s, "about ")
+	s = strings.TrimPrefix(s, "less than ")
+	if rest, ok := strings.CutPrefix(s, "an "); ok {
+		s = "1 " + rest
+	} else if rest, ok := strings.CutPrefix(s, "a "); ok {
+		s = "1 " + rest
+	}
+
+	number, unit := splitNumberPrefix(s)
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	multiplier, ok := durationUnits[unit]
+	if !ok {
+		return 0, false
+	}
+
+	return time.Duration(n * float64(multiplier)), true
+}
@@ -0,0 +1,66 @@
+package tablo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHumanSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"512", 512, true},
+		{"12.7MB", 12.7e6, true},
+		{"10mb", 10e6, true},
+		{"4.0K", 4096, true},
+		{"1GiB", 1 << 30, true},
+		{" 2 kb ", 2000, true},
+		{"12 parsecs", 0, false},
+		{"MB", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseHumanSize(tt.in)
+
+		assert.Equal(t, tt.ok, ok, tt.in)
+		assert.InDelta(t, tt.want, got, 0.001, tt.in)
+	}
+}
+
+func TestIsSizeLiteral(t *testing.T) {
+	assert.True(t, isSizeLiteral("10mb"))
+	assert.True(t, isSizeLiteral("1G"))
+	assert.True(t, isSizeLiteral("100b"))
+	assert.False(t, isSizeLiteral("10m"))
+	assert.False(t, isSizeLiteral("10"))
+	assert.False(t, isSizeLiteral("web"))
+}
+
+func TestParseHumanDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"1h30m", 90 * time.Minute, true},
+		{"3d", 72 * time.Hour, true},
+		{"2w", 14 * 24 * time.Hour, true},
+		{"22 hours ago", 22 * time.Hour, true},
+		{"About an hour ago", time.Hour, true},
+		{"a minute ago", time.Minute, true},
+		{"2 months ago", 60 * 24 * time.Hour, true},
+		{"Up 3 days", 0, false},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseHumanDuration(tt.in)
+
+		assert.Equal(t, tt.ok, ok, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
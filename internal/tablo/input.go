@@ -0,0 +1,469 @@
+package tablo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InputFormat defines how the input is decoded.
+type InputFormat string
+
+// input formats.
+const (
+	InputAuto  InputFormat = "auto"
+	InputText  InputFormat = "text"
+	InputJSON  InputFormat = "json"
+	InputJSONL InputFormat = "jsonl"
+	InputYAML  InputFormat = "yaml"
+	InputFixed InputFormat = "fixed"
+)
+
+const (
+	inputKeySeparator = "."
+	inputValueColumn  = "value"
+	inputItemsKey     = "items"
+	yamlNullTag       = "!!null"
+)
+
+var (
+	inputFormats = []InputFormat{
+		InputAuto,
+		InputText,
+		InputJSON,
+		InputJSONL,
+		InputYAML,
+		InputFixed,
+	}
+	inputFormatAliases = map[string]InputFormat{
+		"ndjson": InputJSONL,
+		"yml":    InputYAML,
+		"fw":     InputFixed,
+	}
+)
+
+func parseInputFormat(s string) (InputFormat, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if format, ok := inputFormatAliases[name]; ok {
+		return format, nil
+	}
+	for _, format := range inputFormats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w, unknown input format %q", ErrInvalidValue, s)
+}
+
+func inputFormatNames() []string {
+	names := make([]string, 0, len(inputFormats))
+	for _, format := range inputFormats {
+		names = append(names, string(format))
+	}
+
+	return names
+}
+
+// sniffInputFormat recognizes JSON documents and JSON Lines, everything
+// else is read as text. YAML is only used when asked for.
+func sniffInputFormat(input string) InputFormat {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" || (trimmed[0] != '[' && trimmed[0] != '{') {
+		return InputText
+	}
+	if json.Valid([]byte(trimmed)) {
+		return InputJSON
+	}
+
+	for line := range strings.SplitSeq(trimmed, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "{") || !json.Valid([]byte(line)) {
+			return InputText
+		}
+	}
+
+	return InputJSONL
+}
+
+// inputField is a key/value pair of a decoded object, objectValue keeps
+// the keys in input order.
+type inputField struct {
+	key   string
+	value any
+}
+
+type objectValue []inputField
+
+func decodeJSONValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf(errorWrapFormat, err)
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		var object objectValue
+		for dec.More() {
+			keyToken, errKey := dec.Token()
+			if errKey != nil {
+				return nil, fmt.Errorf(errorWrapFormat, errKey)
+			}
+			key, _ := keyToken.(string)
+			value, errValue := decodeJSONValue(dec)
+			if errValue != nil {
+				return nil, errValue
+			}
+			object = append(object, inputField{key: key, value: value})
+		}
+		if _, err = dec.Token(); err != nil {
+			return nil, fmt.Errorf(errorWrapFormat, err)
+		}
+
+		return object, nil
+	default:
+		items := []any{}
+		for dec.More() {
+			value, errValue := decodeJSONValue(dec)
+			if errValue != nil {
+				return nil, errValue
+			}
+			items = append(items, value)
+		}
+		if _, err = dec.Token(); err != nil {
+			return nil, fmt.Errorf(errorWrapFormat, err)
+		}
+
+		return items, nil
+	}
+}
+
+func newJSONDecoder(r io.Reader) *json.Decoder {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	return dec
+}
+
+func decodeJSON(input string) ([]any, error) {
+	dec := newJSONDecoder(strings.NewReader(input))
+
+	var documents []any
+	for {
+		value, err := decodeJSONValue(dec)
+		if errors.Is(err, io.EOF) {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w, json input: %w", ErrInvalidValue, err)
+		}
+		documents = append(documents, value)
+	}
+}
+
+func decodeJSONLines(input string) ([]any, error) {
+	var documents []any
+
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	scanner.Buffer(nil, len(input)+1)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		dec := newJSONDecoder(strings.NewReader(line))
+		value, err := decodeJSONValue(dec)
+		if err == nil && dec.More() {
+			err = errors.New("unexpected data after value")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w, jsonl input line %d: %w", ErrInvalidValue, lineNumber, err)
+		}
+		documents = append(documents, value)
+	}
+
+	return documents, nil
+}
+
+func yamlNodeValue(node *yaml.Node) any {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return yamlNodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlNodeValue(node.Alias)
+	case yaml.MappingNode:
+		object := make(objectValue, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			object = append(object, inputField{
+				key:   node.Content[i].Value,
+				value: yamlNodeValue(node.Content[i+1]),
+			})
+		}
+		return object
+	case yaml.SequenceNode:
+		items := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			items = append(items, yamlNodeValue(item))
+		}
+		return items
+	default:
+		if node.Tag == yamlNullTag {
+			return nil
+		}
+		return node.Value
+	}
+}
+
+func decodeYAML(input string) ([]any, error) {
+	dec := yaml.NewDecoder(strings.NewReader(input))
+
+	var documents []any
+	for {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w, yaml input: %w", ErrInvalidValue, err)
+		}
+		documents = append(documents, yamlNodeValue(&node))
+	}
+}
+
+// inputRecords turns decoded documents into records. Arrays are expanded,
+// a top level object with an items array (kubectl lists) is unwrapped and
+// any other object is a single record.
+func inputRecords(documents []any) []any {
+	var records []any
+
+	for _, document := range documents {
+		switch v := document.(type) {
+		case []any:
+			records = append(records, v...)
+		case objectValue:
+			idx := slices.IndexFunc(v, func(field inputField) bool { return field.key == inputItemsKey })
+			if items, ok := fieldItems(v, idx); ok {
+				records = append(records, items...)
+				continue
+			}
+			records = append(records, v)
+		case nil:
+			continue
+		default:
+			records = append(records, v)
+		}
+	}
+
+	return records
+}
+
+func fieldItems(object objectValue, idx int) ([]any, bool) {
+	if idx < 0 {
+		return nil, false
+	}
+	items, ok := object[idx].value.([]any)
+
+	return items, ok
+}
+
+// flattenRecord maps a record to dotted keys, nested objects add their key
+// as prefix. Scalar records use the value column.
+func flattenRecord(prefix string, value any, add func(key, value string)) {
+	object, ok := value.(objectValue)
+	if !ok {
+		if prefix == "" {
+			prefix = inputValueColumn
+		}
+		add(prefix, inputCell(value))
+
+		return
+	}
+
+	for _, field := range object {
+		key := field.key
+		if prefix != "" {
+			key = prefix + inputKeySeparator + key
+		}
+		if nested, isObject := field.value.(objectValue); isObject && len(nested) > 0 {
+			flattenRecord(key, nested, add)
+			continue
+		}
+		add(key, inputCell(field.value))
+	}
+}
+
+// inputCell renders a leaf value, lists of scalars are joined with commas
+// and lists holding objects are rendered as compact JSON.
+func inputCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case []any, objectValue:
+				return inputJSON(v)
+			}
+			items = append(items, inputCell(item))
+		}
+		return strings.Join(items, ",")
+	case objectValue:
+		return inputJSON(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func inputJSON(value any) string {
+	var buf bytes.Buffer
+	writeInputJSON(&buf, value)
+
+	return buf.String()
+}
+
+func writeInputJSON(buf *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case objectValue:
+		buf.WriteString("{")
+		for i, field := range v {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			_ = writeJSONString(buf, field.key)
+			buf.WriteString(":")
+			writeInputJSON(buf, field.value)
+		}
+		buf.WriteString("}")
+	case []any:
+		buf.WriteString("[")
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			writeInputJSON(buf, item)
+		}
+		buf.WriteString("]")
+	case string:
+		_ = writeJSONString(buf, v)
+	case nil:
+		buf.WriteString("null")
+	default:
+		buf.WriteString(fmt.Sprint(v))
+	}
+}
+
+// decodeInputDataset decodes structured input into a dataset. Headers are
+// the union of the flattened keys in order of appearance.
+func decodeInputDataset(input string, format InputFormat) (jsonDataset, error) {
+	var (
+		documents []any
+		err       error
+	)
+	switch format {
+	case InputJSONL:
+		documents, err = decodeJSONLines(input)
+	case InputYAML:
+		documents, err = decodeYAML(input)
+	default:
+		documents, err = decodeJSON(input)
+	}
+	if err != nil {
+		return jsonDataset{}, err
+	}
+
+	dataset := jsonDataset{rows: [][]string{}, hasHeader: true}
+	columns := map[string]int{}
+	for _, record := range inputRecords(documents) {
+		values := map[int]string{}
+		flattenRecord("", record, func(key, value string) {
+			idx, ok := columns[key]
+			if !ok {
+				idx = len(dataset.headers)
+				columns[key] = idx
+				dataset.headers = append(dataset.headers, key)
+			}
+			values[idx] = value
+		})
+
+		row := make([]string, len(dataset.headers))
+		for idx, value := range values {
+			row[idx] = value
+		}
+		dataset.rows = append(dataset.rows, row)
+	}
+	for i, row := range dataset.rows {
+		if missing := len(dataset.headers) - len(row); missing > 0 {
+			dataset.rows[i] = append(row, make([]string, missing)...)
+		}
+	}
+
+	return dataset, nil
+}
+
+// parseRecords splits text input into records or decodes structured input
+// into records led by its known header.
+func (t *Tablo) parseRecords(input string) ([][]string, error) {
+	format := t.InputFormat
+	if len(t.FixedWidths) > 0 {
+		if format != "" && format != InputAuto && format != InputFixed {
+			return nil, fmt.Errorf("%w, widths can not be used with %s input", ErrInvalidValue, format)
+		}
+		format = InputFixed
+	}
+	if format == "" || format == InputAuto {
+		format = sniffInputFormat(input)
+	}
+	t.inputFormat = format
+	switch format {
+	case InputText:
+		return t.splitRecords(t.splitLines(input)), nil
+	case InputFixed:
+		return t.fixedWidthRecords(input), nil
+	}
+
+	dataset, err := decodeInputDataset(input, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(dataset.rows) == 0 {
+		return nil, nil
+	}
+
+	t.recordInput = true
+	t.inputHeaders = dataset.headers
+
+	return append([][]string{dataset.headers}, dataset.rows...), nil
+}
+
+// isHeaderRow reports whether fields form a header row. The keys of
+// decoded structured input always do.
+func (t *Tablo) isHeaderRow(fields []string) bool {
+	if t.inputHeaders != nil && slices.Equal(fields, t.inputHeaders) {
+		return true
+	}
+
+	return looksLikeHeader(fields)
+}
@@ -0,0 +1,157 @@
+package tablo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInputFormat(t *testing.T) {
+	tests := map[string]InputFormat{
+		"auto":   InputAuto,
+		"TEXT":   InputText,
+		"json":   InputJSON,
+		"jsonl":  InputJSONL,
+		"ndjson": InputJSONL,
+		" yaml ": InputYAML,
+		"yml":    InputYAML,
+		"fixed":  InputFixed,
+		"fw":     InputFixed,
+	}
+
+	for in, want := range tests {
+		got, err := parseInputFormat(in)
+
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := parseInputFormat("xml")
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestSniffInputFormat(t *testing.T) {
+	tests := map[string]InputFormat{
+		"":                               InputText,
+		"NAME  AGE\nvigo  42":            InputText,
+		"[INFO] started\n[INFO] stopped": InputText,
+		`[{"a": 1}]`:                     InputJSON,
+		` {"a": 1} `:                     InputJSON,
+		"{\"a\": 1}\n{\"a\": 2}\n":       InputJSONL,
+		"{\"a\": 1}\nnot json":           InputText,
+		"a: 1\nb: 2":                     InputText,
+	}
+
+	for in, want := range tests {
+		assert.Equal(t, want, sniffInputFormat(in), in)
+	}
+}
+
+func TestDecodeInputDataset_JSON(t *testing.T) {
+	input := `[
+  {"name": "web", "meta": {"id": 1, "tags": ["a", "b"], "ports": [{"port": 80}]}, "ok": true},
+  {"name": "db", "extra": null, "meta": {}}
+]`
+
+	dataset, err := decodeInputDataset(input, InputJSON)
+
+	require.NoError(t, err)
+	assert.True(t, dataset.hasHeader)
+	assert.Equal(t, []string{"name", "meta.id", "meta.tags", "meta.ports", "ok", "extra", "meta"}, dataset.headers)
+	assert.Equal(t, [][]string{
+		{"web", "1", "a,b", `[{"port":80}]`, "true", "", ""},
+		{"db", "", "", "", "", "", "{}"},
+	}, dataset.rows)
+}
+
+func TestDecodeInputDataset_JSONItemsAndScalars(t *testing.T) {
+	dataset, err := decodeInputDataset(`{"kind": "List", "items": [{"name": "a"}, {"name": "b"}]}`, InputJSON)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name"}, dataset.headers)
+	assert.Equal(t, [][]string{{"a"}, {"b"}}, dataset.rows)
+
+	dataset, err = decodeInputDataset(`[1, "two", 3.5]`, InputJSON)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"value"}, dataset.headers)
+	assert.Equal(t, [][]string{{"1"}, {"two"}, {"3.5"}}, dataset.rows)
+}
+
+func TestDecodeInputDataset_JSONLines(t *testing.T) {
+	dataset, err := decodeInputDataset("{\"a\": 1, \"b\": \"x\"}\n\n{\"c\": \"y\", \"a\": 2}\n", InputJSONL)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, dataset.headers)
+	assert.Equal(t, [][]string{{"1", "x", ""}, {"2", "", "y"}}, dataset.rows)
+}
+
+func TestDecodeInputDataset_YAML(t *testing.T) {
+	input := `items:
+  - name: web
+    spec:
+      replicas: 3
+      ports: [80, 443]
+  - name: db
+    spec:
+      replicas: ~
+---
+name: cache
+`
+
+	dataset, err := decodeInputDataset(input, InputYAML)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "spec.replicas", "spec.ports"}, dataset.headers)
+	assert.Equal(t, [][]string{
+		{"web", "3", "80,443"},
+		{"db", "", ""},
+		{"cache", "", ""},
+	}, dataset.rows)
+}
+
+func TestDecodeInputDataset_Errors(t *testing.T) {
+	_, err := decodeInputDataset(`[{"a": 1}`, InputJSON)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	_, err = decodeInputDataset("{\"a\": 1}\n{\"a\": 2} 3\n", InputJSONL)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = decodeInputDataset("a: [1, 2", InputYAML)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestTablo_ParseRecords_JSON(t *testing.T) {
+	tbl := &Tablo{FieldDelimiter: ':', RawSplit: true}
+
+	records, err := tbl.parseRecords(`[{"user name": "vigo", "id": "a,\"b\""}]`)
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"user name", "id"}, {"vigo", `a,"b"`}}, records)
+	assert.Equal(t, ':', tbl.FieldDelimiter)
+	assert.True(t, tbl.RawSplit)
+	assert.True(t, tbl.isHeaderRow([]string{"user name", "id"}))
+}
+
+func TestTablo_ParseRecords_EmptyArray(t *testing.T) {
+	tbl := &Tablo{}
+
+	records, err := tbl.parseRecords("[]")
+
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestTablo_RenderJSON_FromJSONInput_SingleColumn(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{Output: nopWriteCloser{&out}}
+
+	records, err := tbl.parseRecords(`[{"@id": "a"}]`)
+	require.NoError(t, err)
+	require.NoError(t, tbl.renderJSON(tbl.buildJSONDataset(records)))
+
+	assert.Equal(t, "[\n  {\n    \"@id\": \"a\"\n  }\n]\n", out.String())
+}
@@ -1,10 +1,12 @@
 package tablo
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type errorWriteCloser struct {
@@ -57,7 +59,7 @@ func TestTablo_BuildJSONDataset_WithSelectedHeaders(t *testing.T) {
 		FieldDelimiter: '|',
 	}
 
-	dataset := tbl.buildJSONDataset([]string{"name|age|city", "vigo"})
+	dataset := tbl.buildJSONDataset(tbl.splitRecords([]string{"name|age|city", "vigo"}))
 
 	assert.True(t, dataset.hasHeader)
 	assert.Equal(t, []string{"name", "age"}, dataset.headers)
@@ -92,7 +94,7 @@ func TestTablo_DetectFieldDelimiter_UsesConfiguredDelimiter(t *testing.T) {
 	assert.Equal(t, ';', delimiter)
 }
 
-func TestTablo_DetectFieldDelimiter_DoesNotAssumeQuotedCSVParsing(t *testing.T) {
+func TestTablo_DetectFieldDelimiter_IgnoresDelimitersInsideQuotes(t *testing.T) {
 	tbl := &Tablo{}
 
 	delimiter := tbl.detectFieldDelimiter([]string{
@@ -100,9 +102,35 @@ func TestTablo_DetectFieldDelimiter_DoesNotAssumeQuotedCSVParsing(t *testing.T)
 		`vigo,"a,b"`,
 	})
 
+	assert.Equal(t, ',', delimiter)
+}
+
+func TestTablo_DetectFieldDelimiter_RawSplitCountsQuotedDelimiters(t *testing.T) {
+	tbl := &Tablo{
+		RawSplit: true,
+	}
+
+	delimiter := tbl.detectFieldDelimiter([]string{
+		`name,notes`,
+		`vigo,"a,b"`,
+	})
+
 	assert.Equal(t, rune(0), delimiter)
 }
 
+func TestTablo_DetectFieldDelimiter_MultiLineQuotedCell(t *testing.T) {
+	tbl := &Tablo{}
+
+	delimiter := tbl.detectFieldDelimiter([]string{
+		`name,notes`,
+		`vigo,"line one`,
+		`line two"`,
+		`john,single`,
+	})
+
+	assert.Equal(t, ',', delimiter)
+}
+
 func TestTablo_DetectFieldDelimiter_MismatchedFieldCountsReturnZero(t *testing.T) {
 	tbl := &Tablo{}
 
@@ -126,12 +154,9 @@ func TestTablo_DetectFieldDelimiter_PreservesLeadingAndTrailingTabs(t *testing.T
 }
 
 func TestTablo_BuildJSONDataset_AutoDetectsCSVDelimiter(t *testing.T) {
-	tbl := &Tablo{}
+	tbl := &Tablo{LineDelimiter: '\n'}
 
-	dataset := tbl.buildJSONDataset([]string{
-		`name,age`,
-		`vigo,42`,
-	})
+	dataset := tbl.buildJSONDataset(tbl.splitRecords(tbl.splitLines("name,age\nvigo,42\n")))
 
 	assert.True(t, dataset.hasHeader)
 	assert.Equal(t, []string{"name", "age"}, dataset.headers)
@@ -145,29 +170,48 @@ func TestTablo_BuildJSONDataset_FilterIndexesTakePrecedence(t *testing.T) {
 		FilterIndexes:  []int{1},
 	}
 
-	dataset := tbl.buildJSONDataset([]string{"name|age", "vigo|42"})
+	dataset := tbl.buildJSONDataset(tbl.splitRecords([]string{"name|age", "vigo|42"}))
 
 	assert.False(t, dataset.hasHeader)
 	assert.Empty(t, dataset.headers)
 	assert.Equal(t, [][]string{{"age"}, {"42"}}, dataset.rows)
 }
 
-func TestTablo_BuildJSONDataset_FilterIndexesWithNoHeaders_SkipsDetectedHeaderRow(t *testing.T) {
+func TestTablo_BuildJSONDataset_FilterIndexesKeepDetectedHeaderRow(t *testing.T) {
 	tbl := &Tablo{
 		FieldDelimiter: ';',
 		FilterIndexes:  []int{0, 2},
-		HideHeaders:    true,
 	}
 
-	dataset := tbl.buildJSONDataset([]string{
+	dataset := tbl.buildJSONDataset(tbl.splitRecords([]string{
 		"Username;Identifier;First name;Last name",
 		"booker12;9012;Rachel;Booker",
-		"grey07;2070;Laura;Grey",
-	})
+	}))
 
 	assert.False(t, dataset.hasHeader)
-	assert.Empty(t, dataset.headers)
-	assert.Equal(t, [][]string{{"booker12", "Rachel"}, {"grey07", "Laura"}}, dataset.rows)
+	assert.True(t, dataset.headerRow)
+	assert.Equal(t, [][]string{{"Username", "First name"}, {"booker12", "Rachel"}}, dataset.rows)
+}
+
+func TestTablo_RenderJSON_FilterIndexesWithNoHeaders_SkipsDetectedHeaderRow(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		ReadInputFunc:  readInput,
+		LineDelimiter:  defaultLineDelimiter,
+		FieldDelimiter: ';',
+		FilterIndexes:  []int{0, 2},
+		HideHeaders:    true,
+		JSONOutput:     true,
+	}
+
+	dataset, err := tbl.Parse(bytes.NewBufferString(
+		"Username;Identifier;First name;Last name\nbooker12;9012;Rachel;Booker\ngrey07;2070;Laura;Grey\n",
+	))
+	require.NoError(t, err)
+	require.NoError(t, tbl.Render(dataset))
+
+	assert.JSONEq(t, `[["booker12", "Rachel"], ["grey07", "Laura"]]`, out.String())
 }
 
 func TestTablo_ShouldSkipFirstRow_FilterIndexesTakePrecedence(t *testing.T) {
@@ -176,7 +220,7 @@ func TestTablo_ShouldSkipFirstRow_FilterIndexesTakePrecedence(t *testing.T) {
 		FilterIndexes: []int{1},
 	}
 
-	skip := tbl.shouldSkipFirstRow([]string{"name|age", "vigo|42"})
+	skip := tbl.shouldSkipFirstRow(tbl.splitRecords([]string{"name|age", "vigo|42"}))
 
 	assert.False(t, skip)
 }
@@ -186,7 +230,7 @@ func TestTablo_ShouldSkipFirstRow_NoHeadersInSmartMode_DoesNotSkip(t *testing.T)
 		HideHeaders: true,
 	}
 
-	skip := tbl.shouldSkipFirstRow([]string{"name  age", "vigo  42"})
+	skip := tbl.shouldSkipFirstRow(tbl.splitRecords([]string{"name  age", "vigo  42"}))
 
 	assert.False(t, skip)
 }
@@ -199,7 +243,7 @@ func TestTablo_RenderJSON_WithoutHeaders_ReturnsWriteError(t *testing.T) {
 		FilterIndexes:  []int{1},
 	}
 
-	err := tbl.renderJSON([]string{"hello|world"})
+	err := tbl.renderJSON(tbl.buildJSONDataset(tbl.splitRecords([]string{"hello|world"})))
 
 	assert.ErrorIs(t, err, writeErr)
 }
@@ -211,7 +255,7 @@ func TestTablo_RenderJSON_WithHeaders_ReturnsWriteError(t *testing.T) {
 		FieldDelimiter: '|',
 	}
 
-	err := tbl.renderJSON([]string{"name|age", "vigo|42"})
+	err := tbl.renderJSON(tbl.buildJSONDataset(tbl.splitRecords([]string{"name|age", "vigo|42"})))
 
 	assert.ErrorIs(t, err, writeErr)
 }
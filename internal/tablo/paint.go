@@ -0,0 +1,273 @@
+package tablo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+const tabSpaces = "    "
+
+type tablePart int
+
+const (
+	tablePartHeader tablePart = iota
+	tablePartRow
+	tablePartFooter
+)
+
+// tablePainter colors go-pretty tables with explicit escape sequences.
+// go-pretty only colors through its process-wide switch, so the cells are
+// padded, aligned and painted here and go-pretty just lays them out; the
+// border lines are painted on the rendered output.
+type tablePainter struct {
+	style   table.Style
+	configs map[int]table.ColumnConfig
+	widths  []int
+}
+
+func newTablePainter(style table.Style, configs []table.ColumnConfig) *tablePainter {
+	p := &tablePainter{style: style, configs: make(map[int]table.ColumnConfig, len(configs))}
+	for _, config := range configs {
+		p.configs[config.Number-1] = config
+	}
+
+	return p
+}
+
+// paintsTable reports whether the style or the -color rules color anything.
+func (t *Tablo) paintsTable(colors table.ColorOptions) bool {
+	if !t.colorOutput() {
+		return false
+	}
+	for _, c := range []text.Colors{
+		colors.Header, colors.Footer, colors.Row, colors.RowAlternate, colors.Border, colors.Separator,
+	} {
+		if len(c) > 0 {
+			return true
+		}
+	}
+
+	return t.colorEnabled()
+}
+
+// renderPaintedTable renders header, rows and footer, already renamed, as a
+// colored go-pretty table. With headerRow the first row is a detected header
+// that is laid out as a row and takes the header colors.
+func (t *Tablo) renderPaintedTable(
+	tw table.Writer, header []string, rows [][]string, footer []string, headerRow bool, configs []table.ColumnConfig,
+) error {
+	p := newTablePainter(*tw.Style(), configs)
+	header = p.stringify(header, tablePartHeader)
+	footer = p.stringify(footer, tablePartFooter)
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = p.stringify(row, tablePartRow)
+	}
+	p.measure(append([][]string{header, footer}, cells...))
+
+	colors := p.style.Color
+	if header != nil {
+		tw.AppendHeader(p.row(header, tablePartHeader, colors.Header))
+	}
+	for i, row := range cells {
+		switch {
+		case i == 0 && headerRow:
+			tw.AppendRow(p.row(row, tablePartRow, colors.Header))
+		case headerRow:
+			tw.AppendRow(p.row(row, tablePartRow, rowColors(colors, i)))
+		default:
+			tw.AppendRow(p.row(row, tablePartRow, rowColors(colors, i+1)))
+		}
+	}
+	if footer != nil {
+		tw.AppendFooter(p.row(footer, tablePartFooter, colors.Footer))
+	}
+	p.layoutStyle(tw.Style())
+
+	out := tw.Render()
+	if out == "" {
+		return nil
+	}
+	if _, err := fmt.Fprintln(t.Output, p.paintRules(out)); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return nil
+}
+
+// stringify turns the fields of a part into cell text the way go-pretty
+// does: -color rules paint the data rows, tabs and carriage returns are
+// expanded.
+func (p *tablePainter) stringify(fields []string, part tablePart) []string {
+	if fields == nil {
+		return nil
+	}
+
+	cells := make([]string, len(fields))
+	for i, field := range fields {
+		if transformer := p.configs[i].Transformer; part == tablePartRow && transformer != nil {
+			field = transformer(field)
+		}
+		cells[i] = text.ProcessCRLF(strings.ReplaceAll(field, "\t", tabSpaces))
+	}
+
+	return cells
+}
+
+// measure sets the column widths go-pretty would use for the cells.
+func (p *tablePainter) measure(rows [][]string) {
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(p.widths) {
+				p.widths = append(p.widths, 0)
+			}
+			width := text.LongestLineLen(cell)
+			if limit := p.configs[i].WidthMax; limit > 0 {
+				width = min(width, limit)
+			}
+			p.widths[i] = max(p.widths[i], width)
+		}
+	}
+}
+
+// row wraps, formats, aligns and pads every cell of a row to its column
+// width and paints it, padding included, with colors.
+func (p *tablePainter) row(cells []string, part tablePart, colors text.Colors) table.Row {
+	lines := make([][]string, len(p.widths))
+	height := 1
+	for i := range lines {
+		cell := fieldAt(cells, i)
+		if config := p.configs[i]; config.WidthMax > 0 {
+			enforce := config.WidthMaxEnforcer
+			if enforce == nil {
+				enforce = text.WrapText
+			}
+			cell = enforce(cell, config.WidthMax)
+		}
+		lines[i] = strings.Split(cell, "\n")
+		height = max(height, len(lines[i]))
+	}
+
+	format := p.style.Format.Row
+	switch part {
+	case tablePartHeader:
+		format = p.style.Format.Header
+	case tablePartFooter:
+		format = p.style.Format.Footer
+	}
+
+	row := make(table.Row, len(lines))
+	for i, cellLines := range lines {
+		align := p.align(i, part)
+		painted := make([]string, height)
+		for j := range painted {
+			line := format.Apply(fieldAt(cellLines, j))
+			line = p.style.Box.PaddingLeft + align.Apply(line, p.widths[i]) + p.style.Box.PaddingRight
+			if len(colors) > 0 {
+				line = text.Escape(line, colors.EscapeSeq())
+			}
+			painted[j] = line
+		}
+		row[i] = strings.Join(painted, "\n")
+	}
+
+	return row
+}
+
+func (p *tablePainter) align(column int, part tablePart) text.Align {
+	config := p.configs[column]
+	align, fallback := config.Align, p.style.Format.RowAlign
+	switch part {
+	case tablePartHeader:
+		align, fallback = config.AlignHeader, p.style.Format.HeaderAlign
+	case tablePartFooter:
+		align, fallback = config.AlignFooter, p.style.Format.FooterAlign
+	}
+	if align == text.AlignDefault {
+		return fallback
+	}
+
+	return align
+}
+
+// frameColors returns the colors of the outer border and of the lines
+// between rows and columns; either one colors both when the other is unset.
+func (p *tablePainter) frameColors() (border, separator text.Colors) {
+	border, separator = p.style.Color.Border, p.style.Color.Separator
+	if len(border) == 0 {
+		border = separator
+	}
+	if len(separator) == 0 {
+		separator = border
+	}
+
+	return border, separator
+}
+
+// layoutStyle leaves go-pretty the layout only: the cells bring their own
+// padding, format and colors, the vertical borders are painted up front.
+func (p *tablePainter) layoutStyle(style *table.Style) {
+	style.Box.PaddingLeft, style.Box.PaddingRight = "", ""
+	style.Format = table.FormatOptions{}
+	style.Color = table.ColorOptionsDefault
+
+	border, separator := p.frameColors()
+	if len(border) > 0 {
+		style.Box.Left = text.Escape(style.Box.Left, border.EscapeSeq())
+		style.Box.Right = text.Escape(style.Box.Right, border.EscapeSeq())
+	}
+	if len(separator) > 0 {
+		style.Box.MiddleVertical = text.Escape(style.Box.MiddleVertical, separator.EscapeSeq())
+	}
+}
+
+// rule returns a horizontal line of the table as go-pretty draws it.
+func (p *tablePainter) rule(left, junction, right string) string {
+	box, options := p.style.Box, p.style.Options
+	padding := text.StringWidthWithoutEscSequences(box.PaddingLeft + box.PaddingRight)
+
+	var b strings.Builder
+	if options.DrawBorder {
+		b.WriteString(left)
+	}
+	for i, width := range p.widths {
+		if i > 0 && options.SeparateColumns {
+			b.WriteString(junction)
+		}
+		b.WriteString(text.RepeatAndTrim(box.MiddleHorizontal, width+padding))
+	}
+	if options.DrawBorder {
+		b.WriteString(right)
+	}
+
+	return b.String()
+}
+
+// paintRules paints the horizontal border and separator lines of a
+// rendered table.
+func (p *tablePainter) paintRules(out string) string {
+	border, separator := p.frameColors()
+	if len(border) == 0 {
+		return out
+	}
+
+	box := p.style.Box
+	top := p.rule(box.TopLeft, box.TopSeparator, box.TopRight)
+	bottom := p.rule(box.BottomLeft, box.BottomSeparator, box.BottomRight)
+	inner := p.rule(box.LeftSeparator, box.MiddleSeparator, box.RightSeparator)
+
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		switch {
+		case (i == 0 && line == top) || (i == len(lines)-1 && line == bottom):
+			lines[i] = text.Escape(line, border.EscapeSeq())
+		case line == inner:
+			lines[i] = text.Escape(line, separator.EscapeSeq())
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,148 @@
+package tablo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTablo_RenderTable_PaintsWithoutGlobalColors(t *testing.T) {
+	text.DisableColors()
+	theme := builtinThemes["light"]
+	var out bytes.Buffer
+	tbl := &Tablo{Output: nopWriteCloser{&out}, ColorMode: ColorAlways, Theme: &theme, SeparateRows: true}
+
+	err := tbl.renderTable(jsonDataset{
+		headers:   []string{"name", "note"},
+		rows:      [][]string{{"web", "a"}, {"db", "b\nc"}},
+		hasHeader: true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "┌──────┬──────┐\n"+
+		"│\x1b[1;34m name \x1b[0m│\x1b[1;34m note \x1b[0m│\n"+
+		"├──────┼──────┤\n"+
+		"│ web  │ a    │\n"+
+		"│\x1b[47m db   \x1b[0m│\x1b[47m b    \x1b[0m│\n"+
+		"│\x1b[47m      \x1b[0m│\x1b[47m c    \x1b[0m│\n"+
+		"└──────┴──────┘\n", out.String())
+	assert.Equal(t, "x", text.Colors{text.FgRed}.Sprint("x"))
+}
+
+func TestTablo_RenderTable_PaintsBorders(t *testing.T) {
+	style := *defaultStyle()
+	style.Box = table.StyleBoxDefault
+	style.Color = table.ColorOptions{Border: text.Colors{text.FgRed}, Separator: text.Colors{text.FgBlue}}
+	var out bytes.Buffer
+	tbl := &Tablo{Output: nopWriteCloser{&out}, ColorMode: ColorAlways, Style: &style}
+
+	err := tbl.renderTable(jsonDataset{headers: []string{"a", "b"}, rows: [][]string{{"1", "2"}}, hasHeader: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, "\x1b[31m+---+---+\x1b[0m\n"+
+		"\x1b[31m|\x1b[0m a \x1b[34m|\x1b[0m b \x1b[31m|\x1b[0m\n"+
+		"\x1b[34m+---+---+\x1b[0m\n"+
+		"\x1b[31m|\x1b[0m 1 \x1b[34m|\x1b[0m 2 \x1b[31m|\x1b[0m\n"+
+		"\x1b[31m+---+---+\x1b[0m\n", out.String())
+}
+
+func TestTablePainter_FrameColors(t *testing.T) {
+	p := newTablePainter(table.Style{Color: table.ColorOptions{Separator: text.Colors{text.FgBlue}}}, nil)
+
+	border, separator := p.frameColors()
+
+	assert.Equal(t, text.Colors{text.FgBlue}, border)
+	assert.Equal(t, text.Colors{text.FgBlue}, separator)
+}
+
+func TestTablo_RenderTable_PaintsWrappedCells(t *testing.T) {
+	_, columns, err := parseMaxWidth("note:5")
+	require.NoError(t, err)
+
+	tests := []struct {
+		mode WidthMode
+		want string
+	}{
+		{WidthWrap, "┌──────┬───────┐\n" +
+			"│\x1b[1;34m name \x1b[0m│\x1b[1;34m note  \x1b[0m│\n" +
+			"├──────┼───────┤\n" +
+			"│ web  │ ab    │\n" +
+			"│\x1b[47m db   \x1b[0m│\x1b[47m hello \x1b[0m│\n" +
+			"│\x1b[47m      \x1b[0m│\x1b[47m world \x1b[0m│\n" +
+			"└──────┴───────┘\n"},
+		{WidthEllipsis, "┌──────┬───────┐\n" +
+			"│\x1b[1;34m name \x1b[0m│\x1b[1;34m note  \x1b[0m│\n" +
+			"├──────┼───────┤\n" +
+			"│ web  │ ab    │\n" +
+			"│\x1b[47m db   \x1b[0m│\x1b[47m hell… \x1b[0m│\n" +
+			"└──────┴───────┘\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			theme := builtinThemes["light"]
+			var out bytes.Buffer
+			tbl := &Tablo{
+				Output:       nopWriteCloser{&out},
+				ColorMode:    ColorAlways,
+				Theme:        &theme,
+				SeparateRows: true,
+				ColumnWidths: columns,
+				WidthMode:    tt.mode,
+			}
+
+			err := tbl.renderTable(jsonDataset{
+				headers:   []string{"name", "note"},
+				rows:      [][]string{{"web", "ab"}, {"db", "hello world"}},
+				hasHeader: true,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestTablo_RenderTable_PaintsBorderOptions(t *testing.T) {
+	style := *defaultStyle()
+	style.Box = table.StyleBoxDefault
+	style.Color = table.ColorOptions{Border: text.Colors{text.FgRed}, Separator: text.Colors{text.FgBlue}}
+	dataset := jsonDataset{headers: []string{"a", "b"}, rows: [][]string{{"1", "2"}, {"3", "4"}}, hasHeader: true}
+
+	tests := []struct {
+		name                     string
+		noBorders, noSeparations bool
+		want                     string
+	}{
+		{"no borders", true, false, " a \x1b[34m|\x1b[0m b \n" +
+			" 1 \x1b[34m|\x1b[0m 2 \n" +
+			"\x1b[34m---+---\x1b[0m\n" +
+			" 3 \x1b[34m|\x1b[0m 4 \n"},
+		{"no separate rows", false, true, "\x1b[31m+---+---+\x1b[0m\n" +
+			"\x1b[31m|\x1b[0m a \x1b[34m|\x1b[0m b \x1b[31m|\x1b[0m\n" +
+			"\x1b[34m+---+---+\x1b[0m\n" +
+			"\x1b[31m|\x1b[0m 1 \x1b[34m|\x1b[0m 2 \x1b[31m|\x1b[0m\n" +
+			"\x1b[31m|\x1b[0m 3 \x1b[34m|\x1b[0m 4 \x1b[31m|\x1b[0m\n" +
+			"\x1b[31m+---+---+\x1b[0m\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tbl := &Tablo{
+				Output:       nopWriteCloser{&out},
+				ColorMode:    ColorAlways,
+				Style:        &style,
+				DrawBorder:   tt.noBorders,
+				SeparateRows: tt.noSeparations,
+			}
+
+			require.NoError(t, tbl.renderTable(dataset))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
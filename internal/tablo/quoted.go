@@ -0,0 +1,179 @@
+package tablo
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const quoteChar = '"'
+
+// splitQuotedFields splits a record on delimiter following RFC 4180 rules:
+// a field that starts with a double quote runs until the matching closing
+// quote, may contain the delimiter or line breaks, and uses "" as an escaped
+// quote. Malformed input is handled leniently; characters after a closing
+// quote are kept and an unterminated quote consumes the rest of the record.
+// The delimiter may be longer than one character.
+func splitQuotedFields(record, delimiter string) []string {
+	var (
+		fields       []string
+		field        strings.Builder
+		inQuotes     bool
+		atFieldStart = true
+	)
+
+	for i := 0; i < len(record); {
+		r, size := utf8.DecodeRuneInString(record[i:])
+
+		switch {
+		case inQuotes:
+			i += size
+			if r != quoteChar {
+				field.WriteRune(r)
+				continue
+			}
+			if i < len(record) && record[i] == quoteChar {
+				field.WriteRune(quoteChar)
+				i++
+				continue
+			}
+			inQuotes = false
+		case strings.HasPrefix(record[i:], delimiter):
+			fields = append(fields, field.String())
+			field.Reset()
+			atFieldStart = true
+			i += len(delimiter)
+			continue
+		case r == quoteChar && atFieldStart:
+			i += size
+			inQuotes = true
+		default:
+			i += size
+			field.WriteRune(r)
+		}
+
+		atFieldStart = false
+	}
+
+	return append(fields, field.String())
+}
+
+// quoteState tracks whether a scan position is inside a quoted field. A
+// quote opens a field only at its start; a doubled quote inside a quoted
+// field closes and immediately reopens it, which keeps the state correct for
+// escaped quotes without inspecting the next rune.
+type quoteState struct {
+	inQuotes     bool
+	closedQuote  bool
+	atFieldStart bool
+}
+
+func newQuoteState() quoteState {
+	return quoteState{atFieldStart: true}
+}
+
+// quoted consumes r and reports whether it is part of a quoted field.
+func (q *quoteState) quoted(r rune) bool {
+	if q.inQuotes {
+		if r == quoteChar {
+			q.inQuotes = false
+			q.closedQuote = true
+		}
+
+		return true
+	}
+
+	if r == quoteChar && (q.atFieldStart || q.closedQuote) {
+		q.inQuotes = true
+		q.closedQuote = false
+		q.atFieldStart = false
+
+		return true
+	}
+
+	q.closedQuote = false
+	q.atFieldStart = false
+
+	return false
+}
+
+func (q *quoteState) startField() {
+	q.atFieldStart = true
+	q.closedQuote = false
+}
+
+// countUnquotedRune counts occurrences of delimiter that are not enclosed in
+// a quoted field.
+func countUnquotedRune(line string, delimiter rune) int {
+	count := 0
+	state := newQuoteState()
+
+	for _, r := range line {
+		if state.quoted(r) {
+			continue
+		}
+		if r == delimiter {
+			count++
+			state.startField()
+		}
+	}
+
+	return count
+}
+
+// splitQuotedRecords splits input into records on lineDelimiter, keeping
+// line delimiters that appear inside quoted fields as part of the cell.
+// Empty records are dropped, matching the plain line splitting behavior.
+func splitQuotedRecords(input, lineDelimiter, fieldDelimiter string) []string {
+	var (
+		records []string
+		record  strings.Builder
+	)
+	state := newQuoteState()
+
+	flush := func() {
+		if record.Len() > 0 {
+			records = append(records, record.String())
+		}
+		record.Reset()
+	}
+
+	for i := 0; i < len(input); {
+		if !state.inQuotes && lineDelimiter != "" && strings.HasPrefix(input[i:], lineDelimiter) {
+			flush()
+			state.startField()
+			i += len(lineDelimiter)
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(input[i:])
+		i += size
+		if state.quoted(r) {
+			record.WriteRune(r)
+			continue
+		}
+
+		record.WriteRune(r)
+		// a field starts after the last character of the delimiter.
+		if fieldDelimiter != "" && strings.HasSuffix(input[:i], fieldDelimiter) {
+			state.startField()
+		}
+	}
+	flush()
+
+	return records
+}
+
+// hasOpenQuote reports whether record ends inside a quoted field, meaning
+// the cell continues on the next line.
+func hasOpenQuote(record, delimiter string) bool {
+	state := newQuoteState()
+	for i := 0; i < len(record); {
+		r, size := utf8.DecodeRuneInString(record[i:])
+		i += size
+		if !state.quoted(r) && delimiter != "" && strings.HasSuffix(record[:i], delimiter) {
+			state.startField()
+		}
+	}
+
+	return state.inQuotes
+}
@@ -0,0 +1,59 @@
+package tablo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitQuotedFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		record    string
+		delimiter string
+		want      []string
+	}{
+		{"plain", "a,b,c", ",", []string{"a", "b", "c"}},
+		{"empty fields", "a,,c,", ",", []string{"a", "", "c", ""}},
+		{"embedded delimiter", `1,"Smith, John",x`, ",", []string{"1", "Smith, John", "x"}},
+		{"escaped quotes", `"say ""hi""",b`, ",", []string{`say "hi"`, "b"}},
+		{"empty quoted", `"",b`, ",", []string{"", "b"}},
+		{"line break", "\"a\nb\",c", ",", []string{"a\nb", "c"}},
+		{"quote inside unquoted field", `5'3",b`, ",", []string{`5'3"`, "b"}},
+		{"text after closing quote", `"a"b,c`, ",", []string{"ab", "c"}},
+		{"unterminated quote", `a,"b,c`, ",", []string{"a", "b,c"}},
+		{"other delimiter", `a;"b;c"`, ";", []string{"a", "b;c"}},
+		{"multi character delimiter", `a::"b::c"::d`, "::", []string{"a", "b::c", "d"}},
+		{"spaced delimiter", `a | "b | c" | d`, " | ", []string{"a", "b | c", "d"}},
+		{"nul delimiter", "a\x00\"b\x00c\"\x00d", "\x00", []string{"a", "b\x00c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitQuotedFields(tt.record, tt.delimiter))
+		})
+	}
+}
+
+func TestCountUnquotedRune(t *testing.T) {
+	assert.Equal(t, 2, countUnquotedRune("a,b,c", ','))
+	assert.Equal(t, 1, countUnquotedRune(`vigo,"a,b"`, ','))
+	assert.Equal(t, 1, countUnquotedRune(`"a "",b""",c`, ','))
+	assert.Equal(t, 0, countUnquotedRune(`"a,b`, ','))
+}
+
+func TestSplitQuotedRecords(t *testing.T) {
+	records := splitQuotedRecords("name,notes\nvigo,\"one\ntwo\"\n\njohn,\"x \"\"\ny\"\"\"\n", "\n", ",")
+
+	assert.Equal(t, []string{
+		"name,notes",
+		"vigo,\"one\ntwo\"",
+		"john,\"x \"\"\ny\"\"\"",
+	}, records)
+}
+
+func TestSplitQuotedRecords_MultiCharacterLineDelimiter(t *testing.T) {
+	records := splitQuotedRecords("name,notes;;vigo,\"a;;b\";;;;john,x", ";;", ",")
+
+	assert.Equal(t, []string{"name,notes", `vigo,"a;;b"`, "john,x"}, records)
+}
@@ -0,0 +1,72 @@
+package tablo
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const renameSeparator = "="
+
+// columnRename renames an output column given by header name or, when index
+// is not negative, by zero-based output column index.
+type columnRename struct {
+	column string
+	index  int
+	name   string
+}
+
+// parseRenames parses a comma separated list of COLUMN=NAME entries where
+// COLUMN is a header name or a 1-based output column index.
+func parseRenames(spec string) ([]columnRename, error) {
+	var renames []columnRename
+
+	for item := range strings.SplitSeq(spec, ",") {
+		column, name, found := strings.Cut(item, renameSeparator)
+		column, name = strings.TrimSpace(column), strings.TrimSpace(name)
+		if !found || column == "" || name == "" {
+			return nil, fmt.Errorf("%w, rename entry %q must be COLUMN=NAME", ErrInvalidValue, strings.TrimSpace(item))
+		}
+
+		rename := columnRename{column: column, index: -1, name: name}
+		if n, err := strconv.Atoi(column); err == nil {
+			if n < 1 {
+				return nil, fmt.Errorf("%w, rename column index %d must be greater than zero", ErrInvalidValue, n)
+			}
+			rename.index = n - 1
+		}
+		renames = append(renames, rename)
+	}
+
+	return renames, nil
+}
+
+// renameHeaders returns a copy of the output headers with -rename applied.
+// Columns are matched against the original names, so renames do not chain.
+func (t *Tablo) renameHeaders(headers []string) ([]string, error) {
+	if len(t.Renames) == 0 {
+		return headers, nil
+	}
+
+	renamed := slices.Clone(headers)
+	indexes := make([]int, 0, len(t.Renames))
+	for _, rename := range t.Renames {
+		idx := rename.index
+		if idx < 0 {
+			idx = slices.IndexFunc(headers, func(header string) bool {
+				return strings.EqualFold(header, rename.column)
+			})
+		}
+		if idx < 0 || idx >= len(headers) {
+			return nil, fmt.Errorf("%w, rename column %q not found", ErrInvalidValue, rename.column)
+		}
+		if slices.Contains(indexes, idx) {
+			return nil, fmt.Errorf("%w, rename column %q is renamed twice", ErrInvalidValue, rename.column)
+		}
+		indexes = append(indexes, idx)
+		renamed[idx] = rename.name
+	}
+
+	return renamed, nil
+}
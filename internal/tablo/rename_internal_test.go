@@ -0,0 +1,48 @@
+package tablo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRenames(t *testing.T) {
+	renames, err := parseRenames("IMAGE ID=id, 3=size_mb")
+
+	require.NoError(t, err)
+	assert.Equal(t, []columnRename{
+		{column: "IMAGE ID", index: -1, name: "id"},
+		{column: "3", index: 2, name: "size_mb"},
+	}, renames)
+
+	for _, spec := range []string{"", "NAME", "NAME=", "=name", "0=name", "a=b,"} {
+		_, err = parseRenames(spec)
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestTablo_RenameHeaders(t *testing.T) {
+	tbl := &Tablo{Renames: []columnRename{
+		{column: "name", index: -1, name: "size"},
+		{column: "size", index: -1, name: "bytes"},
+		{column: "1", index: 0, name: "image"},
+	}}
+	headers := []string{"IMAGE", "NAME", "SIZE"}
+
+	renamed, err := tbl.renameHeaders(headers)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"image", "size", "bytes"}, renamed)
+	assert.Equal(t, []string{"IMAGE", "NAME", "SIZE"}, headers)
+
+	for _, renames := range [][]columnRename{
+		{{column: "missing", index: -1, name: "x"}},
+		{{column: "4", index: 3, name: "x"}},
+		{{column: "name", index: -1, name: "x"}, {column: "2", index: 1, name: "y"}},
+	} {
+		tbl.Renames = renames
+		_, err = tbl.renameHeaders(headers)
+		assert.ErrorIs(t, err, ErrInvalidValue)
+	}
+}
@@ -0,0 +1,255 @@
+package tablo
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	selectorExclude     = "!"
+	selectorRegexQuote  = "/"
+	selectorRangeMarker = "-"
+	selectorGlobChars   = "*?["
+)
+
+// columnSelector is one entry of the column selector grammar used by -fi
+// and the column arguments:
+//
+//	NAME     header name, case insensitive
+//	N, -N    1-based index, negative indexes count from the last column
+//	A-B, A-  index range, open ranges run to the last column
+//	/REGEX/  headers matching the regular expression
+//	GLOB     headers matching a glob such as IMAGE*
+//	!SEL     exclude the columns of SEL
+//
+// A selector that equals a header name always selects that header.
+type columnSelector struct {
+	text    string
+	exclude bool
+	from    int
+	to      int
+	isIndex bool
+	pattern *regexp.Regexp
+	glob    bool
+}
+
+// parseColumnSelector parses a single selector. Indexes are validated here,
+// names and patterns are resolved against the headers later.
+func parseColumnSelector(item string) (columnSelector, error) {
+	sel := columnSelector{}
+	text := strings.TrimSpace(item)
+	if rest, ok := strings.CutPrefix(text, selectorExclude); ok {
+		sel.exclude = true
+		text = rest
+	}
+	sel.text = text
+	if text == "" {
+		return sel, fmt.Errorf("%w, empty column selector %q", ErrInvalidValue, item)
+	}
+
+	if len(text) > 1 && strings.HasPrefix(text, selectorRegexQuote) && strings.HasSuffix(text, selectorRegexQuote) {
+		re, err := regexp.Compile(text[1 : len(text)-1])
+		if err != nil {
+			return sel, fmt.Errorf("%w, column selector %q: %w", ErrInvalidValue, item, err)
+		}
+		sel.pattern = re
+
+		return sel, nil
+	}
+
+	from, to, isIndex, err := parseSelectorIndexes(text)
+	if err != nil {
+		return sel, fmt.Errorf("%w, column selector %q: %w", ErrInvalidValue, item, err)
+	}
+	sel.from, sel.to, sel.isIndex = from, to, isIndex
+	sel.glob = !isIndex && strings.ContainsAny(text, selectorGlobChars)
+	if sel.glob {
+		if _, err = path.Match(text, ""); err != nil {
+			return sel, fmt.Errorf("%w, column selector %q: %w", ErrInvalidValue, item, err)
+		}
+	}
+
+	return sel, nil
+}
+
+// parseSelectorIndexes parses N, -N, A-B and A- forms. It reports false
+// for anything else, which is then taken as a header name.
+func parseSelectorIndexes(text string) (int, int, bool, error) {
+	if n, err := strconv.Atoi(text); err == nil {
+		if n == 0 {
+			return 0, 0, false, fmt.Errorf("column indexes start at 1")
+		}
+
+		return n, n, true, nil
+	}
+
+	start, end, found := strings.Cut(text, selectorRangeMarker)
+	if !found {
+		return 0, 0, false, nil
+	}
+	from, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, false, nil
+	}
+	to := 0
+	if end != "" {
+		if to, err = strconv.Atoi(end); err != nil {
+			return 0, 0, false, nil
+		}
+	}
+
+	switch {
+	case from < 1 || (end != "" && to < 1):
+		return 0, 0, false, fmt.Errorf("column indexes start at 1")
+	case end != "" && to < from:
+		return 0, 0, false, fmt.Errorf("range end %d is before start %d", to, from)
+	}
+
+	return from, to, true, nil
+}
+
+// parseColumnSelectors parses every item as a selector.
+func parseColumnSelectors(items []string) ([]columnSelector, error) {
+	selectors := make([]columnSelector, 0, len(items))
+	for _, item := range items {
+		sel, err := parseColumnSelector(item)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+	}
+
+	return selectors, nil
+}
+
+// indices returns the zero-based columns a selector matches, in column
+// order for ranges and patterns.
+func (sel columnSelector) indices(headers []string, columns int) ([]int, error) {
+	if idx := slices.IndexFunc(headers, func(header string) bool {
+		return strings.EqualFold(header, sel.text)
+	}); idx >= 0 {
+		return []int{idx}, nil
+	}
+
+	var matched []int
+	switch {
+	case sel.pattern != nil || sel.glob:
+		for i, header := range headers {
+			if sel.matches(header) {
+				matched = append(matched, i)
+			}
+		}
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("%w, no column matches %q", ErrInvalidValue, sel.text)
+		}
+	case sel.isIndex:
+		from, to := sel.from, sel.to
+		if from < 0 {
+			from = columns + from + 1
+		}
+		switch {
+		case to < 0:
+			to = columns + to + 1
+		case to == 0:
+			to = columns
+		}
+		if from < 1 || from > columns || to > columns {
+			return nil, fmt.Errorf("%w, column %s is out of range, input has %d columns", ErrInvalidValue, sel.text, columns)
+		}
+		for i := from; i <= to; i++ {
+			matched = append(matched, i-1)
+		}
+	default:
+		return nil, fmt.Errorf("%w, column %q not found", ErrInvalidValue, sel.text)
+	}
+
+	return matched, nil
+}
+
+func (sel columnSelector) matches(header string) bool {
+	if sel.pattern != nil {
+		return sel.pattern.MatchString(header)
+	}
+	ok, _ := path.Match(strings.ToLower(sel.text), strings.ToLower(header))
+
+	return ok
+}
+
+// resolveColumnSelectors returns the selected zero-based columns in
+// selector order. Exclusions apply to the selection, or to all columns
+// when every selector is an exclusion.
+func resolveColumnSelectors(selectors []columnSelector, headers []string, columns int) ([]int, error) {
+	var (
+		selected, excluded []int
+		include            bool
+	)
+	for _, sel := range selectors {
+		matched, err := sel.indices(headers, columns)
+		if err != nil {
+			return nil, err
+		}
+		if sel.exclude {
+			excluded = append(excluded, matched...)
+			continue
+		}
+		include = true
+		selected = append(selected, matched...)
+	}
+
+	if !include {
+		for i := range columns {
+			selected = append(selected, i)
+		}
+	}
+	selected = slices.DeleteFunc(selected, func(idx int) bool { return slices.Contains(excluded, idx) })
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("%w, no columns left to show", ErrInvalidValue)
+	}
+
+	return selected, nil
+}
+
+// argColumnIndices resolves the column arguments against the header row.
+func (t *Tablo) argColumnIndices(headers []string) ([]int, error) {
+	selectors, err := parseColumnSelectors(t.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveColumnSelectors(selectors, headers, len(headers))
+}
+
+// resolveColumnSelection validates the column arguments and turns the -fi
+// selectors into FilterIndexes. Negative indexes and open ranges count the
+// columns of the widest record.
+func (t *Tablo) resolveColumnSelection(records [][]string) error {
+	if len(records) == 0 || (len(t.Args) == 0 && len(t.FilterColumns) == 0) {
+		return nil
+	}
+
+	if len(t.Args) > 0 && len(t.FilterColumns) == 0 {
+		if _, err := t.argColumnIndices(records[0]); err != nil {
+			return err
+		}
+	}
+	if len(t.FilterColumns) == 0 {
+		return nil
+	}
+
+	var columns int
+	for _, fields := range records {
+		columns = max(columns, len(fields))
+	}
+
+	indexes, err := resolveColumnSelectors(t.FilterColumns, t.leadingHeaders(records), columns)
+	if err != nil {
+		return err
+	}
+	t.FilterIndexes = indexes
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package tablo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseColumnSelector(t *testing.T) {
+	sel, err := parseColumnSelector("2-5")
+	require.NoError(t, err)
+	assert.Equal(t, columnSelector{text: "2-5", from: 2, to: 5, isIndex: true}, sel)
+
+	sel, err = parseColumnSelector("3-")
+	require.NoError(t, err)
+	assert.Equal(t, columnSelector{text: "3-", from: 3, isIndex: true}, sel)
+
+	sel, err = parseColumnSelector("-1")
+	require.NoError(t, err)
+	assert.Equal(t, columnSelector{text: "-1", from: -1, to: -1, isIndex: true}, sel)
+
+	sel, err = parseColumnSelector("!PORTS")
+	require.NoError(t, err)
+	assert.Equal(t, columnSelector{text: "PORTS", exclude: true}, sel)
+
+	sel, err = parseColumnSelector("IMAGE*")
+	require.NoError(t, err)
+	assert.True(t, sel.glob)
+
+	sel, err = parseColumnSelector("/^IMAGE/")
+	require.NoError(t, err)
+	assert.NotNil(t, sel.pattern)
+
+	for _, item := range []string{"", "!", "0", "0-2", "3-1", "/[/", "[a"} {
+		_, err = parseColumnSelector(item)
+		assert.ErrorIs(t, err, ErrInvalidValue, item)
+	}
+}
+
+func TestResolveColumnSelectors(t *testing.T) {
+	headers := []string{"CONTAINER ID", "IMAGE", "IMAGE ID", "STATUS", "PORTS", "NAMES"}
+	tests := []struct {
+		spec     []string
+		expected []int
+	}{
+		{spec: []string{"2-4"}, expected: []int{1, 2, 3}},
+		{spec: []string{"5-"}, expected: []int{4, 5}},
+		{spec: []string{"-1", "1"}, expected: []int{5, 0}},
+		{spec: []string{"!PORTS"}, expected: []int{0, 1, 2, 3, 5}},
+		{spec: []string{"/^IMAGE/"}, expected: []int{1, 2}},
+		{spec: []string{"image*", "!image id"}, expected: []int{1}},
+		{spec: []string{"names", "1"}, expected: []int{5, 0}},
+	}
+
+	for _, tt := range tests {
+		selectors, err := parseColumnSelectors(tt.spec)
+		require.NoError(t, err)
+
+		indexes, err := resolveColumnSelectors(selectors, headers, len(headers))
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.expected, indexes, tt.spec)
+	}
+}
+
+func TestResolveColumnSelectors_Invalid(t *testing.T) {
+	headers := []string{"NAME", "SIZE"}
+	for _, spec := range [][]string{{"3"}, {"-3"}, {"1-3"}, {"missing"}, {"/^X/"}, {"!1", "!2"}} {
+		selectors, err := parseColumnSelectors(spec)
+		require.NoError(t, err)
+
+		_, err = resolveColumnSelectors(selectors, headers, len(headers))
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestTablo_ResolveColumnSelection(t *testing.T) {
+	selectors, err := parseColumnSelectors([]string{"-1"})
+	require.NoError(t, err)
+
+	tbl := &Tablo{FieldDelimiter: ',', FilterColumns: selectors}
+	err = tbl.resolveColumnSelection(tbl.splitRecords([]string{"a,b", "c,d,e"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, tbl.FilterIndexes)
+}
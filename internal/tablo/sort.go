@@ -0,0 +1,222 @@
+package tablo
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SortMode defines how the values of a sort column are compared.
+type SortMode int
+
+// sort modes.
+const (
+	SortNatural SortMode = iota
+	SortNumeric
+	SortLexical
+)
+
+const sortSpecSeparator = ":"
+
+// SortKey describes a single sort column. Column holds a header name when
+// Index is negative, otherwise Index is the zero-based column index.
+type SortKey struct {
+	Column     string
+	Index      int
+	Descending bool
+	Mode       SortMode
+}
+
+// parseSortKeys parses a comma separated list of COLUMN[:asc|desc][:mode]
+// entries where COLUMN is a header name or a 1-based column index.
+func parseSortKeys(spec string) ([]SortKey, error) {
+	var keys []SortKey
+
+	for item := range strings.SplitSeq(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return nil, fmt.Errorf("%w, empty sort key in %q", ErrInvalidValue, spec)
+		}
+
+		key := SortKey{Index: -1}
+		parts := strings.Split(item, sortSpecSeparator)
+		for len(parts) > 1 && applySortModifier(&key, parts[len(parts)-1]) {
+			parts = parts[:len(parts)-1]
+		}
+
+		column := strings.Join(parts, sortSpecSeparator)
+		if column == "" {
+			return nil, fmt.Errorf("%w, sort key %q has no column", ErrInvalidValue, item)
+		}
+		if n, err := strconv.Atoi(column); err == nil {
+			if n < 1 {
+				return nil, fmt.Errorf("%w, sort column index %d must be greater than zero", ErrInvalidValue, n)
+			}
+			key.Index = n - 1
+		}
+		key.Column = column
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func applySortModifier(key *SortKey, modifier string) bool {
+	switch strings.ToLower(modifier) {
+	case "asc":
+		key.Descending = false
+	case "desc":
+		key.Descending = true
+	case "natural":
+		key.Mode = SortNatural
+	case "numeric", "num", "n":
+		key.Mode = SortNumeric
+	case "lexical", "lex", "text":
+		key.Mode = SortLexical
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (t *Tablo) resolveSortKeys(headers []string) ([]SortKey, error) {
+	keys := make([]SortKey, len(t.SortKeys))
+	for i, key := range t.SortKeys {
+		if key.Index < 0 {
+			idx := slices.IndexFunc(headers, func(header string) bool {
+				return strings.EqualFold(header, key.Column)
+			})
+			if idx < 0 {
+				return nil, fmt.Errorf("%w, sort column %q not found", ErrInvalidValue, key.Column)
+			}
+			key.Index = idx
+		}
+		keys[i] = key
+	}
+
+	return keys, nil
+}
+
+// sortRecords orders data records by the configured sort keys. A header
+// row (detected or implied by column selection) stays on top.
+func (t *Tablo) sortRecords(records [][]string) ([][]string, error) {
+	if len(t.SortKeys) == 0 || len(records) == 0 {
+		return records, nil
+	}
+
+	headers := t.leadingHeaders(records)
+	keys, err := t.resolveSortKeys(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if headers != nil {
+		start = 1
+	}
+
+	sorted := slices.Clone(records)
+	slices.SortStableFunc(sorted[start:], func(a, b []string) int {
+		for _, key := range keys {
+			c := compareSortValues(fieldAt(a, key.Index), fieldAt(b, key.Index), key.Mode)
+			if c == 0 {
+				continue
+			}
+			if key.Descending {
+				return -c
+			}
+
+			return c
+		}
+
+		return 0
+	})
+
+	return sorted, nil
+}
+
+func fieldAt(fields []string, idx int) string {
+	if idx < len(fields) {
+		return fields[idx]
+	}
+
+	return ""
+}
+
+func compareSortValues(a, b string, mode SortMode) int {
+	switch mode {
+	case SortNumeric:
+		return compareNumeric(a, b)
+	case SortLexical:
+		return strings.Compare(a, b)
+	default:
+		return compareNatural(a, b)
+	}
+}
+
+// compareNumeric orders numbers before non-numeric values, which are
+// compared lexically among themselves.
+func compareNumeric(a, b string) int {
+	na, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	nb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// compareNatural compares digit runs by their numeric value and everything
+// else case-insensitively, so "file2" sorts before "file10".
+func compareNatural(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	i, j := 0, 0
+
+	for i < len(ra) && j < len(rb) {
+		if unicode.IsDigit(ra[i]) && unicode.IsDigit(rb[j]) {
+			si := i
+			for i < len(ra) && unicode.IsDigit(ra[i]) {
+				i++
+			}
+			sj := j
+			for j < len(rb) && unicode.IsDigit(rb[j]) {
+				j++
+			}
+
+			da := strings.TrimLeft(string(ra[si:i]), "0")
+			db := strings.TrimLeft(string(rb[sj:j]), "0")
+			if c := cmp.Compare(len(da), len(db)); c != 0 {
+				return c
+			}
+			if c := strings.Compare(da, db); c != 0 {
+				return c
+			}
+
+			continue
+		}
+
+		ca, cb := unicode.ToLower(ra[i]), unicode.ToLower(rb[j])
+		if c := cmp.Compare(ca, cb); c != 0 {
+			return c
+		}
+		i++
+		j++
+	}
+
+	if c := cmp.Compare(len(ra)-i, len(rb)-j); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,83 @@
+package tablo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	keys, err := parseSortKeys("SIZE:desc:numeric, 2 ,NAME:lexical,a:b:asc")
+
+	require.NoError(t, err)
+	assert.Equal(t, []SortKey{
+		{Column: "SIZE", Index: -1, Descending: true, Mode: SortNumeric},
+		{Column: "2", Index: 1},
+		{Column: "NAME", Index: -1, Mode: SortLexical},
+		{Column: "a:b", Index: -1},
+	}, keys)
+}
+
+func TestParseSortKeys_Invalid(t *testing.T) {
+	for _, spec := range []string{"a,,b", "0", ":desc", "-1"} {
+		_, err := parseSortKeys(spec)
+
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestCompareNatural(t *testing.T) {
+	assert.Negative(t, compareNatural("file2", "file10"))
+	assert.Positive(t, compareNatural("12.7MB", "9MB"))
+	assert.Negative(t, compareNatural("alpha", "Beta"))
+	assert.Negative(t, compareNatural("a", "a1"))
+	assert.Negative(t, compareNatural("a01", "a1"))
+	assert.Zero(t, compareNatural("same", "same"))
+}
+
+func TestCompareNumeric(t *testing.T) {
+	assert.Negative(t, compareNumeric("-2", "10"))
+	assert.Positive(t, compareNumeric("10.5", "9"))
+	assert.Negative(t, compareNumeric("3", "n/a"))
+	assert.Positive(t, compareNumeric("n/a", "3"))
+	assert.Negative(t, compareNumeric("a", "b"))
+}
+
+func TestTablo_SortRecords_KeepsHeaderOnTop(t *testing.T) {
+	tbl := &Tablo{
+		FieldDelimiter: '|',
+		SortKeys:       []SortKey{{Column: "age", Index: -1, Descending: true, Mode: SortNumeric}},
+	}
+
+	records, err := tbl.sortRecords(tbl.splitRecords([]string{"name|age", "vigo|42", "john|7", "jane|100"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"name", "age"}, {"jane", "100"}, {"vigo", "42"}, {"john", "7"}}, records)
+}
+
+func TestTablo_SortRecords_MultipleKeys(t *testing.T) {
+	tbl := &Tablo{
+		FieldDelimiter: ',',
+		SortKeys: []SortKey{
+			{Column: "1", Index: 0, Mode: SortLexical},
+			{Column: "2", Index: 1, Descending: true},
+		},
+	}
+
+	records, err := tbl.sortRecords([][]string{{"b", "1"}, {"a", "2"}, {"b", "10"}, {"a", "1"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "2"}, {"a", "1"}, {"b", "10"}, {"b", "1"}}, records)
+}
+
+func TestTablo_SortRecords_UnknownColumn(t *testing.T) {
+	tbl := &Tablo{
+		FieldDelimiter: '|',
+		SortKeys:       []SortKey{{Column: "missing", Index: -1}},
+	}
+
+	_, err := tbl.sortRecords(tbl.splitRecords([]string{"name|age", "vigo|42"}))
+
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
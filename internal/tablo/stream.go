@@ -0,0 +1,545 @@
+package tablo
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+const (
+	defaultStreamWindow = 20
+	streamSnipIndicator = "…"
+)
+
+// lineReader reads physical lines separated by delimiter. A newline
+// delimiter also ends lines at \r\n and \r. Lines pushed back with unread are
+// returned before reading from the underlying reader.
+type lineReader struct {
+	r         *bufio.Reader
+	delimiter string
+	pending   []string
+}
+
+func newLineReader(r io.Reader, delimiter string) *lineReader {
+	return &lineReader{
+		r:         bufio.NewReader(r),
+		delimiter: delimiter,
+	}
+}
+
+func (lr *lineReader) unread(lines []string) {
+	lr.pending = append(append([]string(nil), lines...), lr.pending...)
+}
+
+func (lr *lineReader) readLine() (string, error) {
+	if len(lr.pending) > 0 {
+		line := lr.pending[0]
+		lr.pending = lr.pending[1:]
+
+		return line, nil
+	}
+
+	var line strings.Builder
+	for {
+		r, _, err := lr.r.ReadRune()
+		if err != nil {
+			if errors.Is(err, io.EOF) && line.Len() > 0 {
+				return line.String(), nil
+			}
+			if errors.Is(err, io.EOF) {
+				return "", io.EOF
+			}
+
+			return "", fmt.Errorf(errorWrapFormat, err)
+		}
+		if r == '\r' && lr.delimiter == string(defaultLineDelimiter) {
+			lr.skipNewline()
+			return line.String(), nil
+		}
+
+		line.WriteRune(r)
+		if lr.delimiter != "" && strings.HasSuffix(line.String(), lr.delimiter) {
+			return strings.TrimSuffix(line.String(), lr.delimiter), nil
+		}
+	}
+}
+
+// skipNewline consumes the \n of a \r\n line ending.
+func (lr *lineReader) skipNewline() {
+	r, _, err := lr.r.ReadRune()
+	if err == nil && r != '\n' {
+		_ = lr.r.UnreadRune()
+	}
+}
+
+// nextRecord returns the next non-empty, non-comment record. Quoted cells
+// of delimited input may continue on the following lines.
+func (t *Tablo) nextRecord(lr *lineReader) (string, error) {
+	for {
+		line, err := lr.readLine()
+		if err != nil {
+			return "", err
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		record := line
+		for t.quotedFields() && hasOpenQuote(record, t.fieldSeparator()) {
+			next, errNext := lr.readLine()
+			if errNext != nil {
+				break
+			}
+			record += t.lineSeparator() + next
+		}
+
+		return record, nil
+	}
+}
+
+// streamWindow reads the look-ahead window used to detect the field
+// delimiter, the header row and the column widths.
+func (t *Tablo) streamWindow(lr *lineReader) ([]string, bool, error) {
+	size := t.StreamWindow
+	if size <= 0 {
+		size = defaultStreamWindow
+	}
+
+	var raw, probe []string
+	for len(probe) < size {
+		line, err := lr.readLine()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		raw = append(raw, line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			probe = append(probe, line)
+		}
+	}
+
+	t.ensureDetectedFieldDelimiter(probe)
+	lr.unread(raw)
+
+	var records []string
+	for len(records) < size {
+		record, err := t.nextRecord(lr)
+		if errors.Is(err, io.EOF) {
+			return records, true, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		records = append(records, record)
+	}
+
+	return records, false, nil
+}
+
+// streamRowWriter writes a single row as soon as it is available.
+type streamRowWriter interface {
+	begin(header []string) error
+	row(fields []string) error
+	end() error
+}
+
+// tabelizeStream renders rows while they are read instead of collecting the
+// whole input first. Column widths come from the look-ahead window and
+// -max-width, wider cells are snipped to keep the columns aligned.
+func (t *Tablo) tabelizeStream(input io.Reader) error {
+	if len(t.SortKeys) > 0 {
+		return fmt.Errorf("%w, sort can not be used in stream mode", ErrInvalidValue)
+	}
+	if len(t.Footer) > 0 {
+		return fmt.Errorf("%w, footer can not be used in stream mode", ErrInvalidValue)
+	}
+	if len(t.GroupBy) > 0 {
+		return fmt.Errorf("%w, group by can not be used in stream mode", ErrInvalidValue)
+	}
+	if t.JSONTypes != "" && t.JSONTypes != JSONTypesStrings {
+		return fmt.Errorf("%w, json types %s can not be used in stream mode", ErrInvalidValue, t.JSONTypes)
+	}
+	if t.InputFormat != "" && t.InputFormat != InputAuto && t.InputFormat != InputText {
+		return fmt.Errorf("%w, %s input can not be used in stream mode", ErrInvalidValue, t.InputFormat)
+	}
+	if len(t.FixedWidths) > 0 {
+		return fmt.Errorf("%w, widths can not be used in stream mode", ErrInvalidValue)
+	}
+	if t.View {
+		return fmt.Errorf("%w, view can not be used in stream mode", ErrInvalidValue)
+	}
+	if t.Format != "" && t.Format != FormatTable && t.Format != FormatJSON {
+		return fmt.Errorf("%w, %s format can not be used in stream mode", ErrInvalidValue, t.Format)
+	}
+
+	t.resetInputState()
+	lr := newLineReader(input, t.lineSeparator())
+	lines, eof, err := t.streamWindow(lr)
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		if t.JSONOutput {
+			return nil
+		}
+
+		return t.newStreamTableWriter(nil).end()
+	}
+
+	window := t.splitRecords(lines)
+	if err = t.resolveColumnSelection(window); err != nil {
+		return err
+	}
+	if err = t.resolveComputedColumns(window); err != nil {
+		return err
+	}
+
+	firstFields := window[0]
+	columnIndices := t.selectColumnIndices(firstFields)
+	headers := t.leadingHeaders(window)
+	if t.Where != nil {
+		if err = resolveWhere(t.Where, headers); err != nil {
+			return err
+		}
+	}
+
+	var header []string
+	if len(t.FilterIndexes) == 0 {
+		switch {
+		case len(columnIndices) > 0:
+			header = pickFieldsByIndices(firstFields, columnIndices)
+		case t.JSONOutput && t.isHeaderRow(firstFields):
+			header = firstFields
+		case !t.JSONOutput && len(t.Args) > 0 && (len(window) > 1 || !eof):
+			header = firstFields
+		}
+		if header != nil {
+			header = t.computedCells(header, nil, true)
+		}
+	}
+	skipFirst := header != nil || t.shouldSkipFirstRow(window)
+	if header == nil && !t.JSONOutput && len(t.Args) > 0 && len(t.FilterIndexes) == 0 {
+		// a lone unmatched header line is rendered as a row.
+		skipFirst = false
+	}
+
+	var rows [][]string
+	accept := func(index int, fields []string) []string {
+		if index == 0 && skipFirst {
+			return nil
+		}
+		if t.Where != nil && !(index == 0 && headers != nil) && !t.Where.eval(fields) {
+			return nil
+		}
+
+		return t.computedCells(t.selectFields(fields, columnIndices), fields, index == 0 && headers != nil)
+	}
+	for i, record := range window {
+		if fields := accept(i, record); fields != nil {
+			rows = append(rows, fields)
+		}
+	}
+
+	columnNames := header
+	if header != nil {
+		if header, err = t.renameHeaders(header); err != nil {
+			return err
+		}
+	}
+
+	var writer streamRowWriter
+	if t.JSONOutput {
+		writer = &streamJSONWriter{output: t.Output}
+	} else {
+		alignHeaders, dataRows, headerRow := columnNames, rows, false
+		if header == nil && headers != nil {
+			// the detected header is rendered as the first row.
+			alignHeaders = t.computedCells(t.selectFields(headers, columnIndices), nil, true)
+			if !skipFirst && len(rows) > 0 {
+				dataRows, headerRow = rows[1:], true
+				if rows[0], err = t.renameHeaders(alignHeaders); err != nil {
+					return err
+				}
+			}
+		}
+		aligns, errAlign := t.columnAlignments(alignHeaders, dataRows)
+		if errAlign != nil {
+			return errAlign
+		}
+		if t.HideHeaders {
+			header = nil
+		}
+		tableWriter := t.newStreamTableWriter(streamColumnWidths(header, rows))
+		tableWriter.aligns = aligns
+		tableWriter.headerRow = headerRow
+		if tableWriter.colors, err = t.columnColors(alignHeaders, len(aligns)); err != nil {
+			return err
+		}
+		tableWriter.widths, err = t.maxColumnWidths(alignHeaders, tableWriter.widths, tableWriter.style())
+		if err != nil {
+			return err
+		}
+		writer = tableWriter
+	}
+
+	if err = writer.begin(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err = writer.row(row); err != nil {
+			return err
+		}
+	}
+
+	for index := len(window); !eof; index++ {
+		record, errRecord := t.nextRecord(lr)
+		if errors.Is(errRecord, io.EOF) {
+			break
+		}
+		if errRecord != nil {
+			return errRecord
+		}
+		if fields := accept(index, t.splitFields(record)); fields != nil {
+			if err = writer.row(fields); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writer.end()
+}
+
+func streamColumnWidths(header []string, rows [][]string) []int {
+	var widths []int
+	measure := func(fields []string) {
+		for i, field := range fields {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], text.StringWidthWithoutEscSequences(streamCell(field)))
+		}
+	}
+
+	measure(header)
+	for _, row := range rows {
+		measure(row)
+	}
+
+	return widths
+}
+
+// streamCell flattens multi-line cells, a streamed row is a single line.
+func streamCell(value string) string {
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+type streamTableWriter struct {
+	output       io.Writer
+	box          table.BoxStyle
+	widths       []int
+	aligns       []text.Align
+	colors       columnColors
+	theme        table.ColorOptions
+	headerRow    bool
+	drawBorder   bool
+	separateRows bool
+	rowCount     int
+	started      bool
+}
+
+func (t *Tablo) newStreamTableWriter(widths []int) *streamTableWriter {
+	style := t.tableStyle()
+	sw := &streamTableWriter{
+		output:       t.Output,
+		box:          style.Box,
+		widths:       widths,
+		theme:        style.Color,
+		drawBorder:   !t.DrawBorder && style.Options.DrawBorder,
+		separateRows: !t.SeparateRows && style.Options.SeparateRows,
+	}
+	if !sw.drawBorder && len(widths) == 1 {
+		sw.box.PaddingLeft = ""
+	}
+
+	return sw
+}
+
+// style returns the borders, separators and padding the rows are drawn
+// with.
+func (sw *streamTableWriter) style() table.Style {
+	return table.Style{
+		Box:     sw.box,
+		Options: table.Options{DrawBorder: sw.drawBorder, SeparateColumns: true},
+	}
+}
+
+func (sw *streamTableWriter) write(s string) error {
+	if _, err := io.WriteString(sw.output, s); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return nil
+}
+
+func (sw *streamTableWriter) line(left, separator, right string) error {
+	if len(sw.widths) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	if sw.drawBorder {
+		b.WriteString(left)
+	}
+	for i, width := range sw.widths {
+		if i > 0 {
+			b.WriteString(separator)
+		}
+		cellWidth := width + text.StringWidth(sw.box.PaddingLeft) + text.StringWidth(sw.box.PaddingRight)
+		b.WriteString(strings.Repeat(sw.box.MiddleHorizontal, cellWidth))
+	}
+	if sw.drawBorder {
+		b.WriteString(right)
+	}
+	b.WriteString("\n")
+
+	return sw.write(b.String())
+}
+
+func (sw *streamTableWriter) cells(fields []string, colors text.Colors, paint bool) error {
+	var b strings.Builder
+	if sw.drawBorder {
+		b.WriteString(sw.box.Left)
+	}
+	for i, width := range sw.widths {
+		if i > 0 {
+			b.WriteString(sw.box.MiddleVertical)
+		}
+		value := streamCell(fieldAt(fields, i))
+		align := text.AlignLeft
+		if i < len(sw.aligns) && sw.aligns[i] != text.AlignDefault {
+			align = sw.aligns[i]
+		}
+		cell := text.Snip(value, width, streamSnipIndicator)
+		if paint {
+			cell = sw.colors.paint(i, value, cell)
+		}
+		cell = sw.box.PaddingLeft + align.Apply(cell, width) + sw.box.PaddingRight
+		if len(colors) > 0 {
+			cell = text.Escape(cell, colors.EscapeSeq())
+		}
+		b.WriteString(cell)
+	}
+	if sw.drawBorder {
+		b.WriteString(sw.box.Right)
+	}
+	b.WriteString("\n")
+
+	return sw.write(b.String())
+}
+
+func (sw *streamTableWriter) begin(header []string) error {
+	sw.started = true
+	if sw.drawBorder {
+		if err := sw.line(sw.box.TopLeft, sw.box.TopSeparator, sw.box.TopRight); err != nil {
+			return err
+		}
+	}
+	if header == nil {
+		return nil
+	}
+	if err := sw.cells(header, sw.theme.Header, false); err != nil {
+		return err
+	}
+	if !sw.drawBorder {
+		return nil
+	}
+
+	return sw.line(sw.box.LeftSeparator, sw.box.MiddleSeparator, sw.box.RightSeparator)
+}
+
+func (sw *streamTableWriter) row(fields []string) error {
+	if sw.rowCount > 0 && sw.separateRows {
+		if err := sw.line(sw.box.LeftSeparator, sw.box.MiddleSeparator, sw.box.RightSeparator); err != nil {
+			return err
+		}
+	}
+	sw.rowCount++
+	if sw.headerRow && sw.rowCount == 1 {
+		return sw.cells(fields, sw.theme.Header, false)
+	}
+	n := sw.rowCount
+	if sw.headerRow {
+		n--
+	}
+
+	return sw.cells(fields, rowColors(sw.theme, n), true)
+}
+
+func (sw *streamTableWriter) end() error {
+	if !sw.started || !sw.drawBorder {
+		return nil
+	}
+
+	return sw.line(sw.box.BottomLeft, sw.box.BottomSeparator, sw.box.BottomRight)
+}
+
+// streamJSONWriter emits one JSON document per row (JSON Lines).
+type streamJSONWriter struct {
+	output io.Writer
+	header []string
+}
+
+func (jw *streamJSONWriter) begin(header []string) error {
+	jw.header = header
+
+	return nil
+}
+
+func (jw *streamJSONWriter) row(fields []string) error {
+	var buf strings.Builder
+	if jw.header == nil {
+		b, err := json.Marshal(fields)
+		if err != nil {
+			return fmt.Errorf(errorWrapFormat, err)
+		}
+		buf.Write(b)
+	} else {
+		buf.WriteString("{")
+		for i, key := range jw.header {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			k, err := json.Marshal(key)
+			if err != nil {
+				return fmt.Errorf(errorWrapFormat, err)
+			}
+			v, err := json.Marshal(fieldAt(fields, i))
+			if err != nil {
+				return fmt.Errorf(errorWrapFormat, err)
+			}
+			buf.Write(k)
+			buf.WriteString(":")
+			buf.Write(v)
+		}
+		buf.WriteString("}")
+	}
+	buf.WriteString("\n")
+
+	if _, err := io.WriteString(jw.output, buf.String()); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return nil
+}
+
+func (*streamJSONWriter) end() error {
+	return nil
+}
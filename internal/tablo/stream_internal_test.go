@@ -0,0 +1,337 @@
+package tablo
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buf.Write(p)
+}
+
+func (*syncBuffer) Close() error { return nil }
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buf.String()
+}
+
+func TestTablo_TabelizeStream_Table(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:        nopWriteCloser{&out},
+		LineDelimiter: '\n',
+		Args:          []string{"name", "age"},
+		StreamWindow:  2,
+	}
+
+	err := tbl.tabelizeStream(strings.NewReader("name,age,city\n# comment\nvigo,42,ist\njohnathan,7,ank\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, `┌──────┬─────┐
+│ name │ age │
+├──────┼─────┤
+│ vigo │  42 │
+├──────┼─────┤
+│ joh… │   7 │
+└──────┴─────┘
+`, out.String())
+}
+
+func TestTablo_TabelizeStream_NoBordersNoSeparateRows(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:        nopWriteCloser{&out},
+		LineDelimiter: '\n',
+		DrawBorder:    true,
+		SeparateRows:  true,
+	}
+
+	err := tbl.tabelizeStream(strings.NewReader("a  b\nccc  d\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, " a   │ b \n ccc │ d \n", out.String())
+}
+
+func TestTablo_TabelizeStream_MaxWidth(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:        nopWriteCloser{&out},
+		LineDelimiter: '\n',
+		SeparateRows:  true,
+		MaxWidth:      20,
+	}
+
+	err := tbl.tabelizeStream(strings.NewReader("name,city\nvigo,istanbul and ankara\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, `┌──────┬───────────┐
+│ name │ city      │
+│ vigo │ istanbul… │
+└──────┴───────────┘
+`, out.String())
+}
+
+func TestTablo_TabelizeStream_JSONLines(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		LineDelimiter:  '\n',
+		FieldDelimiter: ',',
+		JSONOutput:     true,
+		StreamWindow:   1,
+	}
+
+	err := tbl.tabelizeStream(strings.NewReader("name,notes\nvigo,\"a\nb\"\njohn,x\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "{\"name\":\"vigo\",\"notes\":\"a\\nb\"}\n{\"name\":\"john\",\"notes\":\"x\"}\n", out.String())
+}
+
+func TestTablo_TabelizeStream_JSONLinesWithoutHeader(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		LineDelimiter:  '\n',
+		FieldDelimiter: ':',
+		FilterIndexes:  []int{0, 2},
+		JSONOutput:     true,
+	}
+
+	err := tbl.tabelizeStream(strings.NewReader("root:x:0\nnobody:x:-2\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "[\"root\",\"0\"]\n[\"nobody\",\"-2\"]\n", out.String())
+}
+
+func TestTablo_TabelizeStream_JSONLinesRenamed(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		LineDelimiter:  '\n',
+		FieldDelimiter: ',',
+		JSONOutput:     true,
+		Renames:        []columnRename{{column: "IMAGE ID", index: -1, name: "id"}},
+	}
+
+	err := tbl.tabelizeStream(strings.NewReader("NAME,IMAGE ID\nweb,abc\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "{\"NAME\":\"web\",\"id\":\"abc\"}\n", out.String())
+}
+
+func TestTablo_TabelizeStream_Where(t *testing.T) {
+	node, err := parseWhere("age > 10")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		LineDelimiter:  '\n',
+		FieldDelimiter: '|',
+		SeparateRows:   true,
+		Where:          node,
+	}
+
+	err = tbl.tabelizeStream(strings.NewReader("name|age\nvigo|42\nkid|7\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, `┌──────┬─────┐
+│ name │ age │
+│ vigo │  42 │
+└──────┴─────┘
+`, out.String())
+}
+
+func TestTablo_TabelizeStream_Color(t *testing.T) {
+	_, rules, err := parseColor("always,age>10=red")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		LineDelimiter:  '\n',
+		FieldDelimiter: '|',
+		SeparateRows:   true,
+		ColorMode:      ColorAlways,
+		ColorRules:     rules,
+	}
+
+	err = tbl.tabelizeStream(strings.NewReader("name|age\nvigo|42\nkid|7\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "┌──────┬─────┐\n"+
+		"│ name │ age │\n"+
+		"│ vigo │  \x1b[31m42\x1b[0m │\n"+
+		"│ kid  │   7 │\n"+
+		"└──────┴─────┘\n", out.String())
+}
+
+func TestTablo_TabelizeStream_Theme(t *testing.T) {
+	theme := table.ColorOptions{Header: text.Colors{text.Bold}, RowAlternate: text.Colors{text.BgWhite}}
+
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		LineDelimiter:  '\n',
+		FieldDelimiter: '|',
+		SeparateRows:   true,
+		ColorMode:      ColorAlways,
+		Theme:          &theme,
+	}
+
+	err := tbl.tabelizeStream(strings.NewReader("name|age\nvigo|42\nkid|7\nbob|9\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "┌──────┬─────┐\n"+
+		"│\x1b[1m name \x1b[0m│\x1b[1m age \x1b[0m│\n"+
+		"│ vigo │  42 │\n"+
+		"│\x1b[47m kid  \x1b[0m│\x1b[47m   7 \x1b[0m│\n"+
+		"│ bob  │   9 │\n"+
+		"└──────┴─────┘\n", out.String())
+}
+
+func TestTablo_TabelizeStream_SortIsNotSupported(t *testing.T) {
+	tbl := &Tablo{
+		SortKeys: []SortKey{{Column: "1", Index: 0}},
+	}
+
+	err := tbl.tabelizeStream(strings.NewReader("a\n"))
+
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestTablo_TabelizeStream_FixedWidthsAreNotSupported(t *testing.T) {
+	tbl := &Tablo{FixedWidths: []int{4}}
+
+	err := tbl.tabelizeStream(strings.NewReader("a\n"))
+
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestTablo_TabelizeStream_ViewIsNotSupported(t *testing.T) {
+	tbl := &Tablo{View: true}
+
+	err := tbl.tabelizeStream(strings.NewReader("a\n"))
+
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestTablo_TabelizeStream_EmptyInput(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:        nopWriteCloser{&out},
+		LineDelimiter: '\n',
+	}
+
+	err := tbl.tabelizeStream(strings.NewReader(""))
+
+	require.NoError(t, err)
+	assert.Empty(t, out.String())
+}
+
+func TestTablo_TabelizeStream_FlushesRowsBeforeEOF(t *testing.T) {
+	out := new(syncBuffer)
+	tbl := &Tablo{
+		Output:         out,
+		LineDelimiter:  '\n',
+		FieldDelimiter: '|',
+		StreamWindow:   2,
+		SeparateRows:   true,
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() { done <- tbl.tabelizeStream(pr) }()
+
+	_, err := io.WriteString(pw, "name|age\nvigo|42\njohn|7\n")
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "│ john │   7 │")
+	}, time.Second, 10*time.Millisecond)
+	assert.NotContains(t, out.String(), "└")
+
+	require.NoError(t, pw.Close())
+	require.NoError(t, <-done)
+	assert.True(t, strings.HasSuffix(out.String(), "└──────┴─────┘\n"))
+}
+
+func TestLineReader_Unread(t *testing.T) {
+	lr := newLineReader(strings.NewReader("c\nd"), "\n")
+	lr.unread([]string{"a", "b"})
+
+	var lines []string
+	for {
+		line, err := lr.readLine()
+		if err != nil {
+			assert.ErrorIs(t, err, io.EOF)
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, lines)
+}
+
+func TestLineReader_Delimiters(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		delimiter string
+		want      []string
+	}{
+		{"newline", "a\nb\n", "\n", []string{"a", "b"}},
+		{"crlf", "a\r\nb\r\n", "\n", []string{"a", "b"}},
+		{"carriage return", "a\rb", "\n", []string{"a", "b"}},
+		{"nul", "a\x00b\x00", "\x00", []string{"a", "b"}},
+		{"multi character", "a;;b;c;;", ";;", []string{"a", "b;c"}},
+		{"explicit carriage return keeps newline", "a\nb\rc", "\r", []string{"a\nb", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lr := newLineReader(strings.NewReader(tt.input), tt.delimiter)
+
+			var lines []string
+			for {
+				line, err := lr.readLine()
+				if err != nil {
+					assert.ErrorIs(t, err, io.EOF)
+					break
+				}
+				lines = append(lines, line)
+			}
+
+			assert.Equal(t, tt.want, lines)
+		})
+	}
+}
+
+func TestHasOpenQuote(t *testing.T) {
+	assert.True(t, hasOpenQuote(`a,"b`, ","))
+	assert.False(t, hasOpenQuote(`a,"b"`, ","))
+	assert.False(t, hasOpenQuote(`a,b"`, ","))
+	assert.True(t, hasOpenQuote(`a,"b "" c`, ","))
+	assert.True(t, hasOpenQuote(`a::"b`, "::"))
+	assert.False(t, hasOpenQuote(`a:"b`, "::"))
+}
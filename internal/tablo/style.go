@@ -0,0 +1,242 @@
+package tablo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+const defaultStyleName = "light"
+
+var (
+	builtinStyleBoxes = map[string]table.BoxStyle{
+		"double":  table.StyleBoxDouble,
+		"rounded": table.StyleBoxRounded,
+		"bold":    table.StyleBoxBold,
+		"ascii":   table.StyleBoxDefault,
+	}
+	styleFormats = map[string]text.Format{
+		"default": text.FormatDefault,
+		"lower":   text.FormatLower,
+		"title":   text.FormatTitle,
+		"upper":   text.FormatUpper,
+	}
+)
+
+// styleNames returns the built-in style names, default first.
+func styleNames() []string {
+	names := make([]string, 0, len(builtinStyleBoxes))
+	for name := range builtinStyleBoxes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return append([]string{defaultStyleName}, names...)
+}
+
+// defaultStyle is the style used when no -style is given; headers and
+// footers keep their original case and rows are separated unless -n is set.
+func defaultStyle() *table.Style {
+	style := customStyleLight()
+	style.Format.Header = text.FormatDefault
+	style.Format.Footer = text.FormatDefault
+	style.Options.SeparateRows = true
+	style.Options.SeparateFooter = true
+
+	return style
+}
+
+func builtinStyle(name string) (*table.Style, bool) {
+	name = strings.ToLower(name)
+	if name == defaultStyleName {
+		return defaultStyle(), true
+	}
+
+	box, ok := builtinStyleBoxes[name]
+	if !ok {
+		return nil, false
+	}
+
+	style := defaultStyle()
+	style.Name = name
+	style.Box = box
+
+	return style, true
+}
+
+// styleFile describes a user style. Unset fields are inherited from the
+// base style, which defaults to the built-in light style.
+type styleFile struct {
+	Name    string            `json:"name"    toml:"name"`
+	Base    string            `json:"base"    toml:"base"`
+	Box     map[string]string `json:"box"     toml:"box"`
+	Format  map[string]string `json:"format"  toml:"format"`
+	Color   map[string]string `json:"color"   toml:"color"`
+	Options map[string]bool   `json:"options" toml:"options"`
+}
+
+func styleBoxFields(box *table.BoxStyle) map[string]*string {
+	return map[string]*string{
+		"bottom_left":       &box.BottomLeft,
+		"bottom_right":      &box.BottomRight,
+		"bottom_separator":  &box.BottomSeparator,
+		"empty_separator":   &box.EmptySeparator,
+		"left":              &box.Left,
+		"left_separator":    &box.LeftSeparator,
+		"middle_horizontal": &box.MiddleHorizontal,
+		"middle_separator":  &box.MiddleSeparator,
+		"middle_vertical":   &box.MiddleVertical,
+		"padding_left":      &box.PaddingLeft,
+		"padding_right":     &box.PaddingRight,
+		"page_separator":    &box.PageSeparator,
+		"right":             &box.Right,
+		"right_separator":   &box.RightSeparator,
+		"top_left":          &box.TopLeft,
+		"top_right":         &box.TopRight,
+		"top_separator":     &box.TopSeparator,
+		"unfinished_row":    &box.UnfinishedRow,
+	}
+}
+
+func styleFormatFields(format *table.FormatOptions) map[string]*text.Format {
+	return map[string]*text.Format{
+		"footer": &format.Footer,
+		"header": &format.Header,
+		"row":    &format.Row,
+	}
+}
+
+func styleOptionFields(options *table.Options) map[string]*bool {
+	return map[string]*bool{
+		"draw_border":      &options.DrawBorder,
+		"separate_columns": &options.SeparateColumns,
+		"separate_footer":  &options.SeparateFooter,
+		"separate_header":  &options.SeparateHeader,
+		"separate_rows":    &options.SeparateRows,
+	}
+}
+
+// loadStyleFile reads a JSON or TOML style definition.
+func loadStyleFile(path string) (*table.Style, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("%w, %w", ErrInvalidFile, err)
+	}
+
+	var def styleFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		if _, err = toml.Decode(string(b), &def); err != nil {
+			return nil, fmt.Errorf("%w, style file %s: %w", ErrInvalidValue, path, err)
+		}
+	default:
+		if err = json.Unmarshal(b, &def); err != nil {
+			return nil, fmt.Errorf("%w, style file %s: %w", ErrInvalidValue, path, err)
+		}
+	}
+
+	return def.style(path)
+}
+
+func (def styleFile) style(path string) (*table.Style, error) {
+	base := def.Base
+	if base == "" {
+		base = defaultStyleName
+	}
+	style, ok := builtinStyle(base)
+	if !ok {
+		return nil, fmt.Errorf("%w, style file %s: unknown base style %q", ErrInvalidValue, path, base)
+	}
+
+	style.Name = def.Name
+	if style.Name == "" {
+		style.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	boxFields := styleBoxFields(&style.Box)
+	for key, value := range def.Box {
+		field, ok := boxFields[strings.ToLower(key)]
+		if !ok {
+			return nil, fmt.Errorf("%w, style file %s: unknown box key %q", ErrInvalidValue, path, key)
+		}
+		*field = value
+	}
+
+	formatFields := styleFormatFields(&style.Format)
+	for key, value := range def.Format {
+		field, ok := formatFields[strings.ToLower(key)]
+		if !ok {
+			return nil, fmt.Errorf("%w, style file %s: unknown format key %q", ErrInvalidValue, path, key)
+		}
+		format, ok := styleFormats[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("%w, style file %s: unknown format %q", ErrInvalidValue, path, value)
+		}
+		*field = format
+	}
+
+	colorFields := styleColorFields(&style.Color)
+	for key, value := range def.Color {
+		field, ok := colorFields[strings.ToLower(key)]
+		if !ok {
+			return nil, fmt.Errorf("%w, style file %s: unknown color key %q", ErrInvalidValue, path, key)
+		}
+		colors, err := parseColors(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w, style file %s: unknown color %q", ErrInvalidValue, path, value)
+		}
+		*field = colors
+	}
+
+	optionFields := styleOptionFields(&style.Options)
+	for key, value := range def.Options {
+		field, ok := optionFields[strings.ToLower(key)]
+		if !ok {
+			return nil, fmt.Errorf("%w, style file %s: unknown option %q", ErrInvalidValue, path, key)
+		}
+		*field = value
+	}
+
+	return style, nil
+}
+
+// resolveStyle returns a built-in style by name or loads a style file.
+func resolveStyle(nameOrPath string) (*table.Style, error) {
+	if style, ok := builtinStyle(nameOrPath); ok {
+		return style, nil
+	}
+
+	if err := isFile(nameOrPath); err != nil {
+		return nil, fmt.Errorf(
+			"%w, unknown style %q, use one of %s or a style file path",
+			ErrInvalidValue, nameOrPath, strings.Join(styleNames(), ", "),
+		)
+	}
+
+	return loadStyleFile(nameOrPath)
+}
+
+// tableStyle returns the configured style, the built-in light style when
+// none is set. -theme replaces the style colors, which are dropped when the
+// output is not colored.
+func (t *Tablo) tableStyle() table.Style {
+	style := *defaultStyle()
+	if t.Style != nil {
+		style = *t.Style
+	}
+	if t.Theme != nil {
+		style.Color = *t.Theme
+	}
+	if !t.colorOutput() {
+		style.Color = table.ColorOptionsDefault
+	}
+
+	return style
+}
@@ -0,0 +1,121 @@
+package tablo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStyleNames(t *testing.T) {
+	assert.Equal(t, []string{"light", "ascii", "bold", "double", "rounded"}, styleNames())
+}
+
+func TestResolveStyle_Builtin(t *testing.T) {
+	style, err := resolveStyle("Rounded")
+
+	require.NoError(t, err)
+	assert.Equal(t, table.StyleBoxRounded, style.Box)
+	assert.Equal(t, text.FormatDefault, style.Format.Header)
+	assert.True(t, style.Options.SeparateRows)
+
+	style, err = resolveStyle("light")
+
+	require.NoError(t, err)
+	assert.Equal(t, customStyleLight().Box, style.Box)
+}
+
+func TestResolveStyle_Unknown(t *testing.T) {
+	_, err := resolveStyle("fancy")
+
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.ErrorContains(t, err, "light, ascii, bold, double, rounded")
+}
+
+func TestResolveStyle_JSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plain.json")
+	content := `{
+  "base": "ascii",
+  "box": {"middle_horizontal": "=", "padding_left": ""},
+  "format": {"header": "upper"},
+  "options": {"separate_rows": false}
+}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	style, err := resolveStyle(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "plain", style.Name)
+	assert.Equal(t, "=", style.Box.MiddleHorizontal)
+	assert.Empty(t, style.Box.PaddingLeft)
+	assert.Equal(t, "+", style.Box.TopLeft)
+	assert.Equal(t, text.FormatUpper, style.Format.Header)
+	assert.False(t, style.Options.SeparateRows)
+	assert.True(t, style.Options.DrawBorder)
+}
+
+func TestResolveStyle_TOMLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mine.toml")
+	content := `name = "mine"
+
+[box]
+top_left = "*"
+
+[color]
+header = "bold+cyan"
+row_alternate = "bg-hi-black"
+
+[options]
+draw_border = false
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	style, err := resolveStyle(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "mine", style.Name)
+	assert.Equal(t, "*", style.Box.TopLeft)
+	assert.Equal(t, "─", style.Box.MiddleHorizontal)
+	assert.False(t, style.Options.DrawBorder)
+	assert.Equal(t, text.Colors{text.Bold, text.FgCyan}, style.Color.Header)
+	assert.Equal(t, text.Colors{text.BgHiBlack}, style.Color.RowAlternate)
+	assert.Nil(t, style.Color.Row)
+}
+
+func TestResolveStyle_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"bad.json":      `{"box": `,
+		"key.json":      `{"box": {"corner": "+"}}`,
+		"format.json":   `{"format": {"header": "shout"}}`,
+		"option.toml":   "[options]\nzebra = true\n",
+		"base.json":     `{"base": "fancy"}`,
+		"broken.toml":   "name = ",
+		"section.toml":  "[format]\nfooter = \"loud\"\n",
+		"color.json":    `{"color": {"header": "neon"}}`,
+		"colorkey.json": `{"color": {"title": "red"}}`,
+	}
+
+	for name, content := range tests {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		_, err := resolveStyle(path)
+		assert.ErrorIs(t, err, ErrInvalidValue, name)
+	}
+}
+
+func TestTablo_StreamTableWriter_UsesStyle(t *testing.T) {
+	style, err := resolveStyle("ascii")
+	require.NoError(t, err)
+
+	tbl := &Tablo{Style: style}
+	sw := tbl.newStreamTableWriter([]int{3})
+
+	assert.Equal(t, table.StyleBoxDefault, sw.box)
+	assert.True(t, sw.drawBorder)
+}
@@ -6,6 +6,7 @@ package tablo
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -15,9 +16,11 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
@@ -33,15 +36,50 @@ const (
 	helpOutput             = "where to send output, can be file path or stdout"
 	helpLineDelimiterChar  = "line delimiter char to split the input"
 	helpFieldDelimiterChar = "field delimiter char to split the line input"
+	helpFieldDelimiterRe   = "regular expression to split the line input, quoted fields are not parsed"
 	helpNoSeparateRows     = "do not draw separation line under rows"
 	helpNoBorders          = "do not draw borders"
 	helpNoHeaders          = "hide the selected or detected header row"
-	helpFilterIndexes      = "filter columns by index"
+	helpFilterIndexes      = "filter columns by index, range (2-5, 3-, -1), name, /regex/, glob or !exclusion"
 	helpJSONOutput         = "render output as json"
+	helpRawSplit           = "split fields on every delimiter, ignore double quotes"
+	helpSort               = "sort rows by columns, COLUMN[:asc|desc][:natural|numeric|lexical],..."
+	helpWhere              = "filter rows by expression, e.g. 'SIZE > 10mb and NAME ~ ^web'"
+	helpStream             = "render rows as they arrive, json output becomes json lines"
+	helpView               = "browse the table in a terminal pager, plain output when not a terminal"
+	helpStreamWindow       = "number of rows used to infer column widths in stream mode"
+	helpFormat             = "output format: table, json, csv, tsv, markdown, html, latex"
+	helpStyle              = "table style: light, ascii, bold, double, rounded or a json/toml style file"
+	helpTheme              = "header, row and footer colors: dark or light (zebra striped rows)"
+	helpInputFormat        = "input format: auto, text, json, jsonl, yaml, fixed"
+	helpJSONTypes          = "json value types: strings, infer or schema"
+	helpJSONSchema         = "json column types for schema mode, COLUMN:string|int|float|bool|date|auto,..."
+	helpAlign              = "align columns, COLUMN:left|center|right,... (numeric columns align right)"
+	helpMaxWidth           = "max table and column widths, WIDTH,COLUMN:WIDTH,..."
+	helpWidthMode          = "how to shorten wide cells: wrap, truncate or ellipsis"
+	helpFooter             = "footer aggregates, COLUMN:sum|avg|min|max|count,..."
+	helpGroupBy            = "collapse rows into one row per distinct COLUMN,... value"
+	helpAggregates         = "group by aggregates, COLUMN:sum|avg|min|max|count,..."
+	helpRename             = "rename output headers, COLUMN=NAME,..."
+	helpAddColumn          = "add computed columns, NAME=EXPR;..."
+	helpWidths             = "fixed-width input column widths, WIDTH,...[,*] (implies -if fixed)"
+	helpColor              = "color cells, COLUMN~REGEX=COLOR,COLUMN>N=COLOR,... and auto|always|never"
+	helpProfile            = "apply a named profile from the config file"
+	helpShowConfig         = "print the effective configuration and where each value comes from"
+	helpVersion            = "display version information"
+	helpUsage              = "show usage"
+	helpBashCompletion     = "print bash completion script"
+	helpZshCompletion      = "print zsh completion script"
+	helpFishCompletion     = "print fish completion script"
 
 	defaultOutput        = "stdout"
+	defaultBinaryName    = "tablo"
 	defaultLineDelimiter = '\n'
 	defaultSpaceAmount   = 2
+	hexEscapeDigits      = 2
+	unicodeEscapeDigits  = 4
+	hexBase              = 16
+	runeBits             = 32
 	delimiterProbeLines  = 5
 )
 
@@ -50,6 +88,7 @@ var (
 	ErrValueRequired = errors.New("value required")
 	ErrInvalidValue  = errors.New("invalid value")
 	ErrInvalidFile   = errors.New("invalid file")
+	ErrInvalidFlag   = errors.New("invalid flag")
 )
 
 // Tablizer defines main functionality.
@@ -89,6 +128,7 @@ func customStyleLight() *table.Style {
 			Header: text.FormatUpper,
 			Row:    text.FormatDefault,
 		},
+		Color: table.ColorOptionsDefault,
 		Options: table.Options{
 			DrawBorder:      true,
 			SeparateHeader:  true,
@@ -130,6 +170,49 @@ func readInput(input io.Reader) (string, error) {
 	return str, nil
 }
 
+// contextReader stops reading once its context is done. A read that is
+// already blocked on the underlying reader is not interrupted.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, fmt.Errorf(errorWrapFormat, err)
+	}
+
+	n, err := c.r.Read(p)
+	if errors.Is(err, io.EOF) {
+		return n, io.EOF
+	}
+	if err != nil {
+		return n, fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return n, nil
+}
+
+// nopWriteCloser turns a writer into an output that is never closed.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// envLookup returns a getenv function for KEY=VALUE pairs, later pairs win
+// like they do for os/exec.
+func envLookup(env []string) func(string) string {
+	values := make(map[string]string, len(env))
+	for _, pair := range env {
+		if key, value, ok := strings.Cut(pair, "="); ok {
+			values[key] = value
+		}
+	}
+
+	return func(key string) string { return values[key] }
+}
+
 func stringSliceToRow(fields []string) table.Row {
 	row := make(table.Row, len(fields))
 	for i, v := range fields {
@@ -140,11 +223,127 @@ func stringSliceToRow(fields []string) table.Row {
 }
 
 func (t *Tablo) splitFields(line string) []string {
-	if t.FieldDelimiter == 0 {
+	if t.FieldPattern != nil {
+		return t.FieldPattern.Split(line, -1)
+	}
+	if !t.hasFieldDelimiter() {
 		return spaceSplitter(defaultSpaceAmount).Split(line, -1)
 	}
+	if !t.RawSplit {
+		return splitQuotedFields(line, t.fieldSeparator())
+	}
+
+	return strings.Split(line, t.fieldSeparator())
+}
+
+// hasFieldDelimiter reports whether fields are split on a delimiter, given
+// or detected, instead of runs of whitespace.
+func (t *Tablo) hasFieldDelimiter() bool {
+	return t.FieldDelimiter != 0 || t.FieldSeparator != "" || t.FieldPattern != nil || t.detectedDelimiter != 0
+}
+
+// delimited reports whether records were split on a delimiter or decoded
+// from structured input rather than split on runs of whitespace.
+func (t *Tablo) delimited() bool {
+	return t.recordInput || t.hasFieldDelimiter()
+}
+
+// fieldSeparator returns the literal field delimiter, empty for smart split
+// and regex delimiters.
+func (t *Tablo) fieldSeparator() string {
+	if t.FieldSeparator != "" {
+		return t.FieldSeparator
+	}
+	if t.FieldDelimiter != 0 {
+		return string(t.FieldDelimiter)
+	}
+	if t.detectedDelimiter != 0 {
+		return string(t.detectedDelimiter)
+	}
+
+	return ""
+}
+
+// quotedFields reports whether quoted fields may hold the field delimiter.
+func (t *Tablo) quotedFields() bool {
+	return !t.RawSplit && t.fieldSeparator() != "" && t.FieldPattern == nil
+}
+
+func (t *Tablo) countDelimiter(line string, delimiter rune) int {
+	if t.RawSplit {
+		return strings.Count(line, string(delimiter))
+	}
+
+	return countUnquotedRune(line, delimiter)
+}
+
+func dropCommentLines(rawLines []string) []string {
+	var lines []string
+	for _, line := range rawLines {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// lineSeparator returns the line delimiter, empty when the input is not
+// split into lines.
+func (t *Tablo) lineSeparator() string {
+	if t.LineSeparator != "" {
+		return t.LineSeparator
+	}
+	if t.LineDelimiter == 0 {
+		return ""
+	}
+
+	return string(t.LineDelimiter)
+}
+
+// normalizeNewlines turns \r\n and \r line endings into \n when lines are
+// split on newlines, so Windows files do not leave a \r in the last column.
+func (t *Tablo) normalizeNewlines(input string) string {
+	if t.lineSeparator() != string(defaultLineDelimiter) || !strings.ContainsRune(input, '\r') {
+		return input
+	}
+
+	return strings.ReplaceAll(strings.ReplaceAll(input, "\r\n", "\n"), "\r", "\n")
+}
+
+// rawLines splits input on the line delimiter and drops empty lines.
+func (t *Tablo) rawLines(input string) []string {
+	if t.lineSeparator() == "" {
+		if input == "" {
+			return nil
+		}
+
+		return []string{input}
+	}
+
+	var lines []string
+	for line := range strings.SplitSeq(t.normalizeNewlines(input), t.lineSeparator()) {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// splitLines splits input into records. Quoted fields of delimited input
+// may span multiple lines, so once the field delimiter is known the input is
+// re-split with a quote-aware scanner.
+func (t *Tablo) splitLines(input string) []string {
+	lines := dropCommentLines(t.rawLines(input))
+
+	t.ensureDetectedFieldDelimiter(lines)
+	if !t.quotedFields() || !strings.ContainsRune(input, quoteChar) {
+		return lines
+	}
 
-	return strings.Split(line, string(t.FieldDelimiter))
+	return dropCommentLines(splitQuotedRecords(t.normalizeNewlines(input), t.lineSeparator(), t.fieldSeparator()))
 }
 
 func (t *Tablo) detectFieldDelimiter(lines []string) rune {
@@ -153,9 +352,10 @@ func (t *Tablo) detectFieldDelimiter(lines []string) rune {
 	}
 
 	candidates := []rune{',', ';', '\t', '|'}
-	maxLines := min(len(lines), delimiterProbeLines)
 
 	for _, candidate := range candidates {
+		lines := t.probeRecords(lines, candidate)
+		maxLines := min(len(lines), delimiterProbeLines)
 		fieldCount := 0
 		matchedLines := 0
 
@@ -165,7 +365,7 @@ func (t *Tablo) detectFieldDelimiter(lines []string) rune {
 				continue
 			}
 
-			currentCount := strings.Count(line, string(candidate)) + 1
+			currentCount := t.countDelimiter(line, candidate) + 1
 			if currentCount <= 1 {
 				fieldCount = 0
 				break
@@ -189,12 +389,47 @@ func (t *Tablo) detectFieldDelimiter(lines []string) rune {
 	return 0
 }
 
+// probeRecords re-joins lines that belong to a quoted multi-line cell so
+// that delimiter detection counts fields per record instead of per line.
+func (t *Tablo) probeRecords(lines []string, candidate rune) []string {
+	if t.RawSplit {
+		return lines
+	}
+
+	lineDelimiter := t.lineSeparator()
+	if lineDelimiter == "" {
+		lineDelimiter = string(defaultLineDelimiter)
+	}
+	probe := lines[:min(len(lines), delimiterProbeLines)]
+
+	return splitQuotedRecords(strings.Join(probe, lineDelimiter), lineDelimiter, string(candidate))
+}
+
 func (t *Tablo) ensureDetectedFieldDelimiter(lines []string) {
-	if t.FieldDelimiter != 0 {
+	if t.hasFieldDelimiter() {
 		return
 	}
 
-	t.FieldDelimiter = t.detectFieldDelimiter(lines)
+	t.detectedDelimiter = t.detectFieldDelimiter(lines)
+}
+
+// resetInputState forgets what was detected in the previous input, so the
+// same Tablo can read another one.
+func (t *Tablo) resetInputState() {
+	t.inputHeaders = nil
+	t.inputFormat = ""
+	t.recordInput = false
+	t.detectedDelimiter = 0
+}
+
+// splitRecords splits every line into its fields.
+func (t *Tablo) splitRecords(lines []string) [][]string {
+	records := make([][]string, len(lines))
+	for i, line := range lines {
+		records[i] = t.splitFields(line)
+	}
+
+	return records
 }
 
 func (t *Tablo) selectColumnIndices(headers []string) []int {
@@ -202,14 +437,9 @@ func (t *Tablo) selectColumnIndices(headers []string) []int {
 		return nil
 	}
 
-	var columnIndices []int
-	for _, arg := range t.Args {
-		for idx, header := range headers {
-			if strings.EqualFold(header, arg) {
-				columnIndices = append(columnIndices, idx)
-				break
-			}
-		}
+	columnIndices, err := t.argColumnIndices(headers)
+	if err != nil {
+		return nil
 	}
 
 	return columnIndices
@@ -297,43 +527,49 @@ func looksLikeHeader(fields []string) bool {
 	return true
 }
 
+// jsonDataset is the header row and data rows every output is rendered
+// from. inlineHeader marks a header detected in text input, the table draws
+// it as its first row. headerRow marks a header row -fi kept as rows[0].
 type jsonDataset struct {
-	headers   []string
-	rows      [][]string
-	hasHeader bool
+	headers      []string
+	rows         [][]string
+	hasHeader    bool
+	inlineHeader bool
+	headerRow    bool
 }
 
-func (t *Tablo) buildJSONDataset(lines []string) jsonDataset {
-	t.ensureDetectedFieldDelimiter(lines)
-
-	if len(lines) == 0 {
+func (t *Tablo) buildJSONDataset(records [][]string) jsonDataset {
+	if len(records) == 0 {
 		return jsonDataset{
 			rows: [][]string{},
 		}
 	}
 
-	headers := t.splitFields(lines[0])
+	headers := records[0]
 	columnIndices := t.selectColumnIndices(headers)
 
 	dataset := jsonDataset{
-		rows: make([][]string, 0, len(lines)),
+		rows: make([][]string, 0, len(records)),
 	}
 	if len(t.FilterIndexes) == 0 && len(columnIndices) > 0 {
-		dataset.headers = pickFieldsByIndices(headers, columnIndices)
+		dataset.headers = t.computedCells(pickFieldsByIndices(headers, columnIndices), nil, true)
 		dataset.hasHeader = true
-	} else if len(t.FilterIndexes) == 0 && looksLikeHeader(headers) {
-		dataset.headers = headers
+	} else if len(t.FilterIndexes) == 0 && t.isHeaderRow(headers) {
+		dataset.headers = t.computedCells(headers, nil, true)
 		dataset.hasHeader = true
+		dataset.inlineHeader = t.inputHeaders == nil
 	}
 
 	start := 0
-	if dataset.hasHeader || t.shouldSkipFirstRow(lines) {
+	if dataset.hasHeader {
 		start = 1
 	}
+	dataset.headerRow = start == 0 && t.leadingHeaders(records) != nil
 
-	for i := start; i < len(lines); i++ {
-		fields := t.splitFields(lines[i])
-		dataset.rows = append(dataset.rows, t.selectFields(fields, columnIndices))
+	for i := start; i < len(records); i++ {
+		fields := records[i]
+		selected := t.selectFields(fields, columnIndices)
+		dataset.rows = append(dataset.rows, t.computedCells(selected, fields, i == 0 && dataset.headerRow))
 	}
 
 	return dataset
@@ -353,21 +589,72 @@ func writeJSONString(buf *bytes.Buffer, value string) error {
 	return nil
 }
 
-func (t *Tablo) renderJSON(lines []string) error {
-	dataset := t.buildJSONDataset(lines)
+func (t *Tablo) renderJSON(dataset jsonDataset) error {
+	types, err := t.columnTypes(dataset)
+	if err != nil {
+		return err
+	}
+
+	typedRows := make([][]json.RawMessage, len(dataset.rows))
+	if types != nil {
+		for i := range dataset.rows {
+			if typedRows[i], err = typedRow(dataset, i, types); err != nil {
+				return err
+			}
+		}
+	}
+
+	footerCells, footerIndexes, err := t.footerRow(dataset.headers, dataset.rows)
+	if err != nil {
+		return err
+	}
+	keyed := dataset
+	if dataset.hasHeader {
+		if keyed.headers, err = t.renameHeaders(dataset.headers); err != nil {
+			return err
+		}
+	}
+
+	var footer json.RawMessage
+	if footerCells != nil {
+		if footer, err = jsonFooter(keyed, footerCells, footerIndexes, types != nil); err != nil {
+			return err
+		}
+	}
+
+	doc, err := jsonRows(dataset, keyed.headers, typedRows, types != nil)
+	if err != nil {
+		return err
+	}
+	if footer != nil {
+		if doc, err = jsonWithFooter(doc, footer); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(t.Output, "%s\n", doc)
+	if err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return nil
+}
 
+// jsonRows encodes the rows as an array of arrays, or of objects keyed by
+// headers in column order when the dataset has a header row.
+func jsonRows(dataset jsonDataset, headers []string, typedRows [][]json.RawMessage, typed bool) ([]byte, error) {
 	if !dataset.hasHeader {
-		b, err := json.MarshalIndent(dataset.rows, "", "  ")
-		if err != nil {
-			return fmt.Errorf(errorWrapFormat, err)
+		var rows any = dataset.rows
+		if typed {
+			rows = typedRows
 		}
 
-		_, err = fmt.Fprintf(t.Output, "%s\n", b)
+		b, err := json.MarshalIndent(rows, "", "  ")
 		if err != nil {
-			return fmt.Errorf(errorWrapFormat, err)
+			return nil, fmt.Errorf(errorWrapFormat, err)
 		}
 
-		return nil
+		return b, nil
 	}
 
 	var buf bytes.Buffer
@@ -375,22 +662,26 @@ func (t *Tablo) renderJSON(lines []string) error {
 
 	for i, row := range dataset.rows {
 		buf.WriteString("  {\n")
-		for j, header := range dataset.headers {
+		for j, header := range headers {
 			buf.WriteString("    ")
 			if err := writeJSONString(&buf, header); err != nil {
-				return err
+				return nil, err
 			}
 			buf.WriteString(": ")
 
-			value := ""
-			if j < len(row) {
-				value = row[j]
-			}
-			if err := writeJSONString(&buf, value); err != nil {
-				return err
+			if typed {
+				buf.Write(typedRows[i][j])
+			} else {
+				value := ""
+				if j < len(row) {
+					value = row[j]
+				}
+				if err := writeJSONString(&buf, value); err != nil {
+					return nil, err
+				}
 			}
 
-			if j < len(dataset.headers)-1 {
+			if j < len(headers)-1 {
 				buf.WriteString(",")
 			}
 			buf.WriteString("\n")
@@ -403,55 +694,139 @@ func (t *Tablo) renderJSON(lines []string) error {
 		buf.WriteString("\n")
 	}
 
-	buf.WriteString("]\n")
-
-	_, err := t.Output.Write(buf.Bytes())
-	if err != nil {
-		return fmt.Errorf(errorWrapFormat, err)
-	}
+	buf.WriteString("]")
 
-	return nil
+	return buf.Bytes(), nil
 }
 
 // Tablo holds the required params.
 type Tablo struct {
 	Version        string
+	Input          io.Reader
 	Output         io.WriteCloser
+	ErrOutput      io.Writer
 	ReadInputFunc  ReadInputFunc
 	Args           []string
 	FilterIndexes  []int
+	FilterColumns  []columnSelector
 	LineDelimiter  rune
+	LineSeparator  string
 	FieldDelimiter rune
+	FieldSeparator string
+	FieldPattern   *regexp.Regexp
 	DisplayVersion bool
 	SeparateRows   bool
 	DrawBorder     bool
 	HideHeaders    bool
 	JSONOutput     bool
+	RawSplit       bool
+	SortKeys       []SortKey
+	Where          whereNode
+	Stream         bool
+	View           bool
+	StreamWindow   int
+	Format         OutputFormat
+	Style          *table.Style
+	Theme          *table.ColorOptions
+	InputFormat    InputFormat
+	JSONTypes      JSONTypes
+	JSONSchema     []jsonSchemaField
+	Align          []columnAlign
+	MaxWidth       int
+	ColumnWidths   []columnWidth
+	WidthMode      WidthMode
+	Footer         []aggregateField
+	GroupBy        []groupKey
+	Aggregates     []aggregateField
+	Renames        []columnRename
+	AddColumns     []computedColumn
+	FixedWidths    []int
+	ColorMode      ColorMode
+	ColorRules     []colorRule
+
+	inputHeaders      []string
+	inputFormat       InputFormat
+	recordInput       bool
+	detectedDelimiter rune
+	ctx               context.Context
+	getenv            func(string) string
 }
 
 func (t *Tablo) setDefaults() {
+	if t.Input == nil {
+		t.Input = os.Stdin
+	}
 	if t.Output == nil {
 		t.Output = os.Stdout
 	}
+	if t.ErrOutput == nil {
+		t.ErrOutput = os.Stderr
+	}
+	if t.ctx == nil {
+		t.ctx = context.Background()
+	}
+	if t.getenv == nil {
+		t.getenv = os.Getenv
+	}
 	if t.ReadInputFunc == nil {
 		t.ReadInputFunc = readInput
 	}
 	t.Version = Version
 }
 
+// env returns an environment variable of the configured environment.
+func (t *Tablo) env(key string) string {
+	if t.getenv == nil {
+		return os.Getenv(key)
+	}
+
+	return t.getenv(key)
+}
+
 // pipe handlers.
 var (
 	IsNamedPipe  = func(f os.FileInfo) bool { return f.Mode()&os.ModeNamedPipe != 0 }
 	IsCharDevice = func(f os.FileInfo) bool { return f.Mode()&os.ModeCharDevice != 0 }
 )
 
-func (t *Tablo) parseArgs() (string, error) {
-	finfo, finfoErr := os.Stdin.Stat()
-	if finfoErr != nil {
-		return "", fmt.Errorf(errorWrapFormat, finfoErr)
+// inputStat returns the file info of the input, ok is false when the input
+// is not a file.
+func (t *Tablo) inputStat() (os.FileInfo, bool, error) {
+	f, ok := t.Input.(*os.File)
+	if !ok {
+		return nil, false, nil
+	}
+
+	finfo, err := f.Stat()
+	if err != nil {
+		return nil, false, fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return finfo, true, nil
+}
+
+// pipedInput reports whether input is piped data, the arguments are all
+// columns then. An input that is not a file counts as piped.
+func pipedInput(input io.Reader) (bool, error) {
+	f, ok := input.(*os.File)
+	if !ok {
+		return true, nil
+	}
+
+	finfo, err := f.Stat()
+	if err != nil {
+		return false, fmt.Errorf(errorWrapFormat, err)
 	}
 
-	if len(t.Args) == 0 || (len(t.Args) > 0 && IsNamedPipe(finfo)) {
+	return IsNamedPipe(finfo), nil
+}
+
+func (t *Tablo) parseArgs() (string, error) {
+	piped, err := pipedInput(t.Input)
+	if err != nil {
+		return "", err
+	}
+	if len(t.Args) == 0 || piped {
 		return "", nil
 	}
 
@@ -464,101 +839,75 @@ func (t *Tablo) parseArgs() (string, error) {
 	return fileArg, nil
 }
 
-func (t *Tablo) getReadFrom() (*os.File, error) {
+func (t *Tablo) getReadFrom() (io.ReadCloser, error) {
 	fileArg, err := t.parseArgs()
 	if err != nil {
 		return nil, err
 	}
 
-	readFrom := os.Stdin
 	if fileArg != "" {
 		file, errF := os.Open(filepath.Clean(fileArg))
 		if errF != nil {
 			return nil, fmt.Errorf(errorWrapFormat, errF)
 		}
 
-		readFrom = file
+		return file, nil
 	}
 
-	if readFrom == os.Stdin {
-		finfo, finfoErr := os.Stdin.Stat()
-		if finfoErr != nil {
-			return nil, fmt.Errorf(errorWrapFormat, finfoErr)
-		}
-
-		if IsCharDevice(finfo) {
-			if runtime.GOOS == "windows" {
-				fmt.Fprintln(t.Output, breakTextForWindows)
-			} else {
-				fmt.Fprintln(t.Output, breakTextForUnix)
-			}
+	finfo, fromFile, err := t.inputStat()
+	if err != nil {
+		return nil, err
+	}
+	if fromFile && IsCharDevice(finfo) {
+		if runtime.GOOS == "windows" {
+			fmt.Fprintln(t.Output, breakTextForWindows)
+		} else {
+			fmt.Fprintln(t.Output, breakTextForUnix)
 		}
 	}
 
-	return readFrom, nil
+	return io.NopCloser(t.Input), nil
 }
 
-func (t *Tablo) processHeaders(tw table.Writer, lines []string) []int {
-	if len(lines) == 0 || len(t.FilterIndexes) > 0 || len(t.Args) == 0 {
-		return nil
+func (t *Tablo) shouldSkipFirstRow(records [][]string) bool {
+	if len(records) == 0 {
+		return false
 	}
 
-	headers := t.splitFields(lines[0])
-	columnIndices := t.selectColumnIndices(headers)
-
-	if len(columnIndices) > 0 {
-		selectedHeaders := pickFieldsByIndices(headers, columnIndices)
-		if !t.HideHeaders {
-			tw.AppendHeader(stringSliceToRow(selectedHeaders))
-		}
-	} else {
-		if len(lines) > 1 {
-			if !t.HideHeaders {
-				tw.AppendHeader(stringSliceToRow(headers))
-			}
-		} else if !t.HideHeaders {
-			tw.AppendRow(stringSliceToRow(headers))
-		}
+	if len(t.FilterIndexes) > 0 {
+		return t.HideHeaders && t.delimited() && t.isHeaderRow(records[0])
 	}
 
-	return columnIndices
-}
-
-func (t *Tablo) processRows(tw table.Writer, lines []string, columnIndices []int) {
-	for i, line := range lines {
-		if i == 0 && t.shouldSkipFirstRow(lines) {
-			continue
-		}
-		fields := t.splitFields(line)
-		selectedFields := t.selectFields(fields, columnIndices)
-		tw.AppendRow(stringSliceToRow(selectedFields))
+	if len(t.Args) > 0 || t.inputHeaders != nil {
+		return true
 	}
-}
 
-func (t *Tablo) shouldSkipFirstRow(lines []string) bool {
-	if len(lines) == 0 {
+	if !t.HideHeaders || !t.delimited() {
 		return false
 	}
 
-	if len(t.FilterIndexes) > 0 {
-		return t.HideHeaders && t.FieldDelimiter != 0 && looksLikeHeader(t.splitFields(lines[0]))
-	}
+	return t.isHeaderRow(records[0])
+}
 
-	if len(t.Args) > 0 {
-		return true
+// leadingHeaders returns the first record's fields when they act as a
+// header row, either detected or implied by column selection, otherwise nil.
+func (t *Tablo) leadingHeaders(records [][]string) []string {
+	if len(records) == 0 {
+		return nil
 	}
 
-	if !t.HideHeaders || t.FieldDelimiter == 0 {
-		return false
+	fields := records[0]
+	if len(t.Args) > 0 || t.isHeaderRow(fields) {
+		return fields
 	}
 
-	return looksLikeHeader(t.splitFields(lines[0]))
+	return nil
 }
 
 // Tabelize generates tablized output.
 func (t *Tablo) Tabelize() error {
 	if t.DisplayVersion {
-		fmt.Fprintf(flag.CommandLine.Output(), "%s\n", t.Version)
+		fmt.Fprintf(t.ErrOutput, "%s\n", t.Version)
 		return nil
 	}
 	readFrom, err := t.getReadFrom()
@@ -566,55 +915,96 @@ func (t *Tablo) Tabelize() error {
 		return err
 	}
 
-	defer func() {
-		if readFrom != os.Stdin {
-			_ = readFrom.Close()
-		}
-	}()
-
-	input, err := t.ReadInputFunc(readFrom)
-	if err != nil {
-		return err
-	}
-
-	rawLines := strings.FieldsFunc(input, func(r rune) bool {
-		return r == t.LineDelimiter
-	})
+	defer func() { _ = readFrom.Close() }()
 
-	var lines []string
-	for _, line := range rawLines {
-		if strings.HasPrefix(line, "#") {
-			continue
-		}
-		lines = append(lines, line)
+	input := contextReader{ctx: t.ctx, r: readFrom}
+	if t.Stream {
+		return t.tabelizeStream(input)
 	}
 
-	if t.JSONOutput {
-		return t.renderJSON(lines)
+	dataset, err := t.Parse(input)
+	if err != nil {
+		return err
 	}
 
-	t.ensureDetectedFieldDelimiter(lines)
+	return t.Render(dataset)
+}
 
+// renderTable renders the dataset as a go-pretty table.
+func (t *Tablo) renderTable(dataset jsonDataset) error {
 	drawBorders := !t.DrawBorder
 	drawSeparateRowsLine := !t.SeparateRows
 
 	tw := table.NewWriter()
-	tw.SetOutputMirror(t.Output)
-	tw.SetStyle(*customStyleLight())
-	tw.Style().Format.Header = text.FormatDefault
-	tw.Style().Options.SeparateRows = drawSeparateRowsLine
+	tw.SetStyle(t.tableStyle())
+	drawBorders = drawBorders && tw.Style().Options.DrawBorder
+	tw.Style().Options.SeparateRows = drawSeparateRowsLine && tw.Style().Options.SeparateRows
 	tw.Style().Options.DrawBorder = drawBorders
 
-	headerColumnIndices := t.processHeaders(tw, lines)
-	t.processRows(tw, lines, headerColumnIndices)
+	headers, rows, headerRow := dataset.headers, dataset.rows, dataset.headerRow
+	if dataset.inlineHeader {
+		rows, headerRow = append([][]string{headers}, rows...), true
+	}
+	if headerRow {
+		headers = rows[0]
+	}
+	dataRows := rows
+	if headerRow {
+		dataRows = rows[1:]
+	}
+
+	renamed := headers
+	if headers != nil {
+		var err error
+		if renamed, err = t.renameHeaders(headers); err != nil {
+			return err
+		}
+	}
+	var header []string
+	if dataset.hasHeader && !dataset.inlineHeader && !t.HideHeaders {
+		header = renamed
+	}
+	body := rows
+	if headerRow {
+		body = append([][]string{renamed}, dataRows...)
+	}
 
 	if !drawBorders {
 		tw.Style().Options.SeparateHeader = false
-		if len(headerColumnIndices) == 1 {
+		if len(t.Args) > 0 && len(headers) == 1 {
 			tw.Style().Box.PaddingLeft = ""
 		}
 	}
 
+	footer, _, err := t.footerRow(headers, dataRows)
+	if err != nil {
+		return err
+	}
+
+	widthMax, err := t.widthLimits(headers, slices.Concat(rows, [][]string{footer}), *tw.Style())
+	if err != nil {
+		return err
+	}
+	columnConfigs, err := t.columnConfigs(headers, dataRows, widthMax)
+	if err != nil {
+		return err
+	}
+
+	if t.paintsTable(tw.Style().Color) {
+		return t.renderPaintedTable(tw, header, body, footer, headerRow, columnConfigs)
+	}
+
+	if header != nil {
+		tw.AppendHeader(stringSliceToRow(header))
+	}
+	for _, row := range body {
+		tw.AppendRow(stringSliceToRow(row))
+	}
+	if footer != nil {
+		tw.AppendFooter(stringSliceToRow(footer))
+	}
+	tw.SetColumnConfigs(columnConfigs)
+	tw.SetOutputMirror(t.Output)
 	tw.Render()
 
 	return nil
@@ -663,69 +1053,197 @@ func WithOutputWriter(wr io.WriteCloser) Option {
 	}
 }
 
-// WithDisplayVersion sets the display version information or not.
-func WithDisplayVersion(display bool) Option {
+// WithInput sets the input that is read when no file argument is given.
+func WithInput(r io.Reader) Option {
 	return func(t *Tablo) error {
-		t.DisplayVersion = display
+		if r == nil {
+			return fmt.Errorf("%w, input reader is nil", ErrValueRequired)
+		}
+		t.Input = r
 
 		return nil
 	}
 }
 
-// WithReadInputFunc sets the read input function.
-func WithReadInputFunc(fn ReadInputFunc) Option {
+// WithErrOutput sets the writer of the version information.
+func WithErrOutput(wr io.Writer) Option {
 	return func(t *Tablo) error {
-		if fn == nil {
-			return fmt.Errorf("%w, read input function is nil", ErrValueRequired)
+		if wr == nil {
+			return fmt.Errorf("%w, error output writer is nil", ErrValueRequired)
 		}
-		t.ReadInputFunc = fn
+		t.ErrOutput = wr
 
 		return nil
 	}
 }
 
-func parseSpecialChars(s string) rune {
-	switch s {
-	case "\\t", "\t":
-		return '\t'
-	case "\\n", "\n":
-		return '\n'
-	case "\\r", "\r":
-		return '\r'
-	case "\\b", "\b":
-		return '\b'
-	case "\\f", "\f":
-		return '\f'
-	case "\\a", "\a":
-		return '\a'
-	default:
-		return rune(s[0])
-	}
-}
-
-// WithLineDelimiter sets the line delimiter.
-func WithLineDelimiter(s string) Option {
+// WithContext sets the context that stops reading the input when done.
+func WithContext(ctx context.Context) Option {
 	return func(t *Tablo) error {
-		if s == "" {
-			return fmt.Errorf("%w, line delimiter is empty string", ErrValueRequired)
+		if ctx == nil {
+			return fmt.Errorf("%w, context is nil", ErrValueRequired)
 		}
-
-		t.LineDelimiter = parseSpecialChars(s)
+		t.ctx = ctx
 
 		return nil
 	}
 }
 
-// WithFieldDelimiter sets the field delimiter.
-func WithFieldDelimiter(s string) Option {
+// WithEnv sets the environment as KEY=VALUE pairs, the process environment
+// is used by default.
+func WithEnv(env []string) Option {
+	return func(t *Tablo) error {
+		t.getenv = envLookup(env)
+
+		return nil
+	}
+}
+
+// WithDisplayVersion sets the display version information or not.
+func WithDisplayVersion(display bool) Option {
+	return func(t *Tablo) error {
+		t.DisplayVersion = display
+
+		return nil
+	}
+}
+
+// WithReadInputFunc sets the read input function.
+func WithReadInputFunc(fn ReadInputFunc) Option {
+	return func(t *Tablo) error {
+		if fn == nil {
+			return fmt.Errorf("%w, read input function is nil", ErrValueRequired)
+		}
+		t.ReadInputFunc = fn
+
+		return nil
+	}
+}
+
+var delimiterEscapes = map[byte]string{
+	't':  "\t",
+	'n':  "\n",
+	'r':  "\r",
+	'b':  "\b",
+	'f':  "\f",
+	'a':  "\a",
+	'0':  "\x00",
+	'\\': "\\",
+}
+
+// parseEscapes decodes the \t, \n, \r, \b, \f, \a, \0, \\, \xNN and
+// \uNNNN escapes of a delimiter.
+func parseEscapes(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		if escaped, ok := delimiterEscapes[s[i]]; ok {
+			b.WriteString(escaped)
+			continue
+		}
+
+		digits := 0
+		switch s[i] {
+		case 'x':
+			digits = hexEscapeDigits
+		case 'u':
+			digits = unicodeEscapeDigits
+		default:
+			return "", fmt.Errorf("%w, unknown escape \\%c in %q", ErrInvalidValue, s[i], s)
+		}
+		if i+digits >= len(s) {
+			return "", fmt.Errorf("%w, short escape \\%c in %q", ErrInvalidValue, s[i], s)
+		}
+		code, err := strconv.ParseUint(s[i+1:i+1+digits], hexBase, runeBits)
+		if err != nil {
+			return "", fmt.Errorf("%w, invalid escape \\%s in %q", ErrInvalidValue, s[i:i+1+digits], s)
+		}
+		b.WriteRune(rune(code))
+		i += digits
+	}
+
+	return b.String(), nil
+}
+
+// parseDelimiter decodes the escapes of a delimiter. A single character is
+// returned as a rune, NUL and longer delimiters as a string because a zero
+// rune means the delimiter is not set.
+func parseDelimiter(s string) (rune, string, error) {
+	delimiter, err := parseEscapes(s)
+	if err != nil {
+		return 0, "", err
+	}
+	if r, size := utf8.DecodeRuneInString(delimiter); size == len(delimiter) && r != 0 {
+		return r, "", nil
+	}
+
+	return 0, delimiter, nil
+}
+
+// WithLineDelimiter sets the line delimiter, a single character, NUL or a
+// longer record separator. The default newline also matches \r\n and \r.
+func WithLineDelimiter(s string) Option {
+	return func(t *Tablo) error {
+		if s == "" {
+			return fmt.Errorf("%w, line delimiter is empty string", ErrValueRequired)
+		}
+
+		var err error
+		t.LineDelimiter, t.LineSeparator, err = parseDelimiter(s)
+
+		return err
+	}
+}
+
+// WithFieldDelimiter sets the field delimiter, a single character or a
+// longer literal such as "::".
+func WithFieldDelimiter(s string) Option {
 	return func(t *Tablo) error {
 		if s == "" {
 			t.FieldDelimiter = 0
+			t.FieldSeparator = ""
 
 			return nil
 		}
+		if t.FieldPattern != nil {
+			return fmt.Errorf("%w, field delimiter can not be used with a field delimiter regex", ErrInvalidValue)
+		}
+
+		return t.setFieldDelimiter(s)
+	}
+}
+
+func (t *Tablo) setFieldDelimiter(s string) error {
+	var err error
+	t.FieldDelimiter, t.FieldSeparator, err = parseDelimiter(s)
 
-		t.FieldDelimiter = parseSpecialChars(s)
+	return err
+}
+
+// WithFieldDelimiterRegex splits fields on matches of a regular expression.
+// Quoted fields are not parsed in this mode.
+func WithFieldDelimiterRegex(pattern string) Option {
+	return func(t *Tablo) error {
+		if pattern == "" {
+			return nil
+		}
+		if t.hasFieldDelimiter() {
+			return fmt.Errorf("%w, field delimiter regex can not be used with a field delimiter", ErrInvalidValue)
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%w, field delimiter regex %q: %w", ErrInvalidValue, pattern, err)
+		}
+		if re.MatchString("") {
+			return fmt.Errorf("%w, field delimiter regex %q matches an empty string", ErrInvalidValue, pattern)
+		}
+		t.FieldPattern = re
 
 		return nil
 	}
@@ -767,30 +1285,377 @@ func WithJSONOutput(enabled bool) Option {
 	}
 }
 
-// WithFilterIndexes sets the filter index columns.
-func WithFilterIndexes(indexes string) Option {
+// WithFormat sets the output format.
+func WithFormat(format string) Option {
 	return func(t *Tablo) error {
-		if indexes == "" {
+		if format == "" {
 			return nil
 		}
 
-		ss := strings.Split(indexes, ",")
-		if len(ss) > 0 {
-			idxes := make([]int, len(ss))
-			for i, v := range ss {
-				n, err := strconv.Atoi(v)
-				if err != nil {
-					return fmt.Errorf("%w, %s is not a number", ErrInvalidValue, v)
-				}
+		outputFormat, err := parseOutputFormat(format)
+		if err != nil {
+			return err
+		}
+		t.Format = outputFormat
+		if outputFormat == FormatJSON {
+			t.JSONOutput = true
+		}
 
-				if n > 0 {
-					idxes[i] = n - 1
-				}
-			}
+		return nil
+	}
+}
 
-			t.FilterIndexes = idxes
+// WithStyle sets the table style by built-in name or style file path.
+func WithStyle(style string) Option {
+	return func(t *Tablo) error {
+		if style == "" {
+			return nil
 		}
 
+		tableStyle, err := resolveStyle(style)
+		if err != nil {
+			return err
+		}
+		t.Style = tableStyle
+
+		return nil
+	}
+}
+
+// WithTheme sets the header, row and footer colors by theme name.
+func WithTheme(name string) Option {
+	return func(t *Tablo) error {
+		if name == "" {
+			return nil
+		}
+
+		theme, err := resolveTheme(name)
+		if err != nil {
+			return err
+		}
+		t.Theme = &theme
+
+		return nil
+	}
+}
+
+// WithInputFormat sets the input format, auto detects json and json lines.
+func WithInputFormat(format string) Option {
+	return func(t *Tablo) error {
+		if format == "" {
+			return nil
+		}
+
+		inputFormat, err := parseInputFormat(format)
+		if err != nil {
+			return err
+		}
+		t.InputFormat = inputFormat
+
+		return nil
+	}
+}
+
+// WithJSONTypes sets how json output values are typed.
+func WithJSONTypes(mode string) Option {
+	return func(t *Tablo) error {
+		if mode == "" {
+			return nil
+		}
+
+		jsonTypes, err := parseJSONTypes(mode)
+		if err != nil {
+			return err
+		}
+		if len(t.JSONSchema) > 0 && jsonTypes != JSONTypesSchema {
+			return fmt.Errorf("%w, json types %s can not be used with a json schema", ErrInvalidValue, jsonTypes)
+		}
+		t.JSONTypes = jsonTypes
+
+		return nil
+	}
+}
+
+// WithJSONSchema sets explicit json column types, implies schema mode.
+func WithJSONSchema(spec string) Option {
+	return func(t *Tablo) error {
+		if spec == "" {
+			return nil
+		}
+
+		if t.JSONTypes != "" && t.JSONTypes != JSONTypesSchema {
+			return fmt.Errorf("%w, json types %s can not be used with a json schema", ErrInvalidValue, t.JSONTypes)
+		}
+		schema, err := parseJSONSchema(spec)
+		if err != nil {
+			return err
+		}
+		t.JSONSchema = schema
+		t.JSONTypes = JSONTypesSchema
+
+		return nil
+	}
+}
+
+// WithAlign sets column alignments.
+func WithAlign(spec string) Option {
+	return func(t *Tablo) error {
+		if spec == "" {
+			return nil
+		}
+
+		aligns, err := parseColumnAligns(spec)
+		if err != nil {
+			return err
+		}
+		t.Align = aligns
+
+		return nil
+	}
+}
+
+// WithMaxWidth sets the table and column width limits.
+func WithMaxWidth(spec string) Option {
+	return func(t *Tablo) error {
+		if spec == "" {
+			return nil
+		}
+
+		tableWidth, columns, err := parseMaxWidth(spec)
+		if err != nil {
+			return err
+		}
+		t.MaxWidth = tableWidth
+		t.ColumnWidths = columns
+
+		return nil
+	}
+}
+
+// WithWidthMode sets how cells wider than their column are shortened.
+func WithWidthMode(mode string) Option {
+	return func(t *Tablo) error {
+		if mode == "" {
+			return nil
+		}
+
+		widthMode, err := parseWidthMode(mode)
+		if err != nil {
+			return err
+		}
+		t.WidthMode = widthMode
+
+		return nil
+	}
+}
+
+// WithFooter sets the footer aggregates.
+func WithFooter(spec string) Option {
+	return func(t *Tablo) error {
+		if spec == "" {
+			return nil
+		}
+
+		fields, err := parseAggregates("footer", spec)
+		if err != nil {
+			return err
+		}
+		t.Footer = fields
+
+		return nil
+	}
+}
+
+// WithGroupBy sets the group by columns.
+func WithGroupBy(spec string) Option {
+	return func(t *Tablo) error {
+		if spec == "" {
+			return nil
+		}
+
+		keys, err := parseGroupBy(spec)
+		if err != nil {
+			return err
+		}
+		t.GroupBy = keys
+
+		return nil
+	}
+}
+
+// WithAggregates sets the per group aggregates.
+func WithAggregates(spec string) Option {
+	return func(t *Tablo) error {
+		if spec == "" {
+			return nil
+		}
+
+		fields, err := parseAggregates("agg", spec)
+		if err != nil {
+			return err
+		}
+		t.Aggregates = fields
+
+		return nil
+	}
+}
+
+// WithRenames sets the output header renames.
+func WithRenames(spec string) Option {
+	return func(t *Tablo) error {
+		if spec == "" {
+			return nil
+		}
+
+		renames, err := parseRenames(spec)
+		if err != nil {
+			return err
+		}
+		t.Renames = renames
+
+		return nil
+	}
+}
+
+// WithAddColumns sets the computed columns.
+func WithAddColumns(spec string) Option {
+	return func(t *Tablo) error {
+		if spec == "" {
+			return nil
+		}
+
+		columns, err := parseComputedColumns(spec)
+		if err != nil {
+			return err
+		}
+		t.AddColumns = columns
+
+		return nil
+	}
+}
+
+// WithFixedWidths sets the fixed-width input column widths.
+func WithFixedWidths(spec string) Option {
+	return func(t *Tablo) error {
+		if spec == "" {
+			return nil
+		}
+
+		widths, err := parseFixedWidths(spec)
+		if err != nil {
+			return err
+		}
+		t.FixedWidths = widths
+
+		return nil
+	}
+}
+
+// WithColor sets the color rules and the color mode.
+func WithColor(spec string) Option {
+	return func(t *Tablo) error {
+		if spec == "" {
+			return nil
+		}
+
+		mode, rules, err := parseColor(spec)
+		if err != nil {
+			return err
+		}
+		t.ColorMode = mode
+		t.ColorRules = rules
+
+		return nil
+	}
+}
+
+// WithRawSplit disables RFC 4180 quote handling for delimited input.
+func WithRawSplit(raw bool) Option {
+	return func(t *Tablo) error {
+		t.RawSplit = raw
+
+		return nil
+	}
+}
+
+// WithSort sets the sort keys from a comma separated spec.
+func WithSort(spec string) Option {
+	return func(t *Tablo) error {
+		if spec == "" {
+			return nil
+		}
+
+		keys, err := parseSortKeys(spec)
+		if err != nil {
+			return err
+		}
+		t.SortKeys = keys
+
+		return nil
+	}
+}
+
+// WithWhere sets the row filter expression.
+func WithWhere(expr string) Option {
+	return func(t *Tablo) error {
+		if expr == "" {
+			return nil
+		}
+
+		node, err := parseWhere(expr)
+		if err != nil {
+			return err
+		}
+		t.Where = node
+
+		return nil
+	}
+}
+
+// WithStream enables streaming mode.
+func WithStream(enabled bool) Option {
+	return func(t *Tablo) error {
+		t.Stream = enabled
+
+		return nil
+	}
+}
+
+// WithView enables the terminal pager.
+func WithView(enabled bool) Option {
+	return func(t *Tablo) error {
+		t.View = enabled
+
+		return nil
+	}
+}
+
+// WithStreamWindow sets the look-ahead window size of streaming mode.
+func WithStreamWindow(size int) Option {
+	return func(t *Tablo) error {
+		if size < 1 {
+			return fmt.Errorf("%w, stream window must be greater than zero", ErrInvalidValue)
+		}
+		t.StreamWindow = size
+
+		return nil
+	}
+}
+
+// WithFilterIndexes sets the filter columns, a comma separated list of
+// column selectors.
+func WithFilterIndexes(indexes string) Option {
+	return func(t *Tablo) error {
+		if indexes == "" {
+			return nil
+		}
+
+		selectors, err := parseColumnSelectors(strings.Split(indexes, ","))
+		if err != nil {
+			return err
+		}
+		t.FilterColumns = selectors
+
 		return nil
 	}
 }
@@ -810,87 +1675,237 @@ func New(options ...Option) (*Tablo, error) {
 	return tbl, nil
 }
 
-// Run runs the command.
+// Run runs the command with the process arguments, standard streams and
+// environment.
 func Run() error {
-	if len(os.Args) > 1 {
-		skipNext := false
-		for i, arg := range os.Args[1:] {
-			if skipNext {
-				skipNext = false
-				continue
-			}
-			if arg == "--" {
-				break
-			}
-			flagName, _, hasInlineValue := completionFlagToken(arg)
-			if completionHasValueFlag(flagName) {
-				if !hasInlineValue {
-					skipNext = true
-				}
-				continue
-			}
-			if !strings.HasPrefix(arg, "-") || arg == "-" {
-				break
-			}
-			switch arg {
-			case shortBashCompletionFlag, bashCompletionFlag:
-				_, err := fmt.Fprint(os.Stdout, bashCompletionScript(filepath.Base(os.Args[0])))
-				if err != nil {
-					return fmt.Errorf(errorWrapFormat, err)
-				}
+	return RunWith(context.Background(), os.Args, os.Stdin, os.Stdout, os.Stderr, os.Environ())
+}
+
+// RunWith runs the command with its own flag set. args start with the
+// program name like os.Args and env holds KEY=VALUE pairs like os.Environ.
+// A stdin that is not a file is read as piped input.
+func RunWith(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, env []string) error {
+	getenv := envLookup(env)
+	binaryName := defaultBinaryName
+	if len(args) > 0 {
+		binaryName, args = args[0], args[1:]
+	}
 
-				return nil
-			case completeFlag:
-				return runCompletion(os.Args[i+2:], os.Stdout)
+	skipNext := false
+	for i, arg := range args {
+		if skipNext {
+			skipNext = false
+			continue
+		}
+		if arg == "--" {
+			break
+		}
+		flagName, _, hasInlineValue := completionFlagToken(arg)
+		if completionHasValueFlag(flagName) {
+			if !hasInlineValue {
+				skipNext = true
 			}
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			break
+		}
+		switch arg {
+		case shortBashCompletionFlag, bashCompletionFlag:
+			return writeCompletionScript(stdout, bashCompletionScript(filepath.Base(binaryName)))
+		case shortZshCompletionFlag, zshCompletionFlag:
+			return writeCompletionScript(stdout, zshCompletionScript(filepath.Base(binaryName)))
+		case shortFishCompletionFlag, fishCompletionFlag:
+			return writeCompletionScript(stdout, fishCompletionScript(filepath.Base(binaryName)))
+		case completeFlag:
+			return runCompletion(args[i+1:], stdout, getenv)
 		}
 	}
 
-	flag.Usage = showUsage
-	flag.CommandLine.Usage = showUsage
+	flags := flag.NewFlagSet(binaryName, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = usageFunc(flags, binaryName, getenv)
 
-	version := flag.Bool("version", false, "display version information")
+	version := flags.Bool("version", false, helpVersion)
 
-	fieldDelimiterChar := flag.String("field-delimiter-char", "", helpFieldDelimiterChar)
-	flag.StringVar(fieldDelimiterChar, "f", "", helpFieldDelimiterChar+" (short)")
+	fieldDelimiterChar := flags.String("field-delimiter-char", "", helpFieldDelimiterChar)
+	flags.StringVar(fieldDelimiterChar, "f", "", helpFieldDelimiterChar+" (short)")
 
-	lineDelimiterChar := flag.String("line-delimiter-char", string(defaultLineDelimiter), helpLineDelimiterChar)
-	flag.StringVar(lineDelimiterChar, "l", string(defaultLineDelimiter), helpLineDelimiterChar+" (short)")
+	fieldDelimiterRegex := flags.String("field-delimiter-regex", "", helpFieldDelimiterRe)
+	flags.StringVar(fieldDelimiterRegex, "f-regex", "", helpFieldDelimiterRe+" (short)")
 
-	noSeparateRows := flag.Bool("no-separate-rows", false, helpNoSeparateRows)
-	flag.BoolVar(noSeparateRows, "n", false, helpNoSeparateRows+" (short)")
+	lineDelimiterChar := flags.String("line-delimiter-char", string(defaultLineDelimiter), helpLineDelimiterChar)
+	flags.StringVar(lineDelimiterChar, "l", string(defaultLineDelimiter), helpLineDelimiterChar+" (short)")
 
-	noBorders := flag.Bool("no-borders", false, helpNoBorders)
-	flag.BoolVar(noBorders, "nb", false, helpNoBorders+" (short)")
+	noSeparateRows := flags.Bool("no-separate-rows", false, helpNoSeparateRows)
+	flags.BoolVar(noSeparateRows, "n", false, helpNoSeparateRows+" (short)")
 
-	noHeaders := flag.Bool("no-headers", false, helpNoHeaders)
-	flag.BoolVar(noHeaders, "nh", false, helpNoHeaders+" (short)")
+	noBorders := flags.Bool("no-borders", false, helpNoBorders)
+	flags.BoolVar(noBorders, "nb", false, helpNoBorders+" (short)")
 
-	filterIndexes := flag.String("filter-indexes", "", helpFilterIndexes)
-	flag.StringVar(filterIndexes, "fi", "", helpFilterIndexes+" (short)")
+	noHeaders := flags.Bool("no-headers", false, helpNoHeaders)
+	flags.BoolVar(noHeaders, "nh", false, helpNoHeaders+" (short)")
 
-	jsonOutput := flag.Bool("json", false, helpJSONOutput)
-	flag.BoolVar(jsonOutput, "j", false, helpJSONOutput+" (short)")
+	filterIndexes := flags.String("filter-indexes", "", helpFilterIndexes)
+	flags.StringVar(filterIndexes, "fi", "", helpFilterIndexes+" (short)")
 
-	output := flag.String("output", defaultOutput, helpOutput)
-	flag.StringVar(output, "o", defaultOutput, helpOutput+" (short)")
+	jsonOutput := flags.Bool("json", false, helpJSONOutput)
+	flags.BoolVar(jsonOutput, "j", false, helpJSONOutput+" (short)")
 
-	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
-		return fmt.Errorf(errorWrapFormat, err)
+	rawSplit := flags.Bool("raw-split", false, helpRawSplit)
+	flags.BoolVar(rawSplit, "rs", false, helpRawSplit+" (short)")
+
+	sortSpec := flags.String("sort", "", helpSort)
+	flags.StringVar(sortSpec, "s", "", helpSort+" (short)")
+
+	where := flags.String("where", "", helpWhere)
+	flags.StringVar(where, "w", "", helpWhere+" (short)")
+
+	stream := flags.Bool("stream", false, helpStream)
+	flags.BoolVar(stream, "st", false, helpStream+" (short)")
+
+	view := flags.Bool("view", false, helpView)
+	flags.BoolVar(view, "vw", false, helpView+" (short)")
+
+	streamWindow := flags.Int("stream-window", defaultStreamWindow, helpStreamWindow)
+	flags.IntVar(streamWindow, "sw", defaultStreamWindow, helpStreamWindow+" (short)")
+
+	format := flags.String("format", string(FormatTable), helpFormat)
+	flags.StringVar(format, "fmt", string(FormatTable), helpFormat+" (short)")
+
+	style := flags.String("style", defaultStyleName, helpStyle)
+	flags.StringVar(style, "sy", defaultStyleName, helpStyle+" (short)")
+
+	theme := flags.String("theme", "", helpTheme)
+	flags.StringVar(theme, "th", "", helpTheme+" (short)")
+
+	output := flags.String("output", defaultOutput, helpOutput)
+	flags.StringVar(output, "o", defaultOutput, helpOutput+" (short)")
+
+	inputFormat := flags.String("input-format", string(InputAuto), helpInputFormat)
+	flags.StringVar(inputFormat, "if", string(InputAuto), helpInputFormat+" (short)")
+
+	widths := flags.String("widths", "", helpWidths)
+	flags.StringVar(widths, "fw", "", helpWidths+" (short)")
+
+	color := flags.String("color", "", helpColor)
+	flags.StringVar(color, "c", "", helpColor+" (short)")
+
+	align := flags.String("align", "", helpAlign)
+	flags.StringVar(align, "a", "", helpAlign+" (short)")
+
+	maxWidth := flags.String("max-width", "", helpMaxWidth)
+	flags.StringVar(maxWidth, "mw", "", helpMaxWidth+" (short)")
+
+	widthMode := flags.String("width-mode", string(WidthWrap), helpWidthMode)
+	flags.StringVar(widthMode, "wm", string(WidthWrap), helpWidthMode+" (short)")
+
+	footer := flags.String("footer", "", helpFooter)
+	flags.StringVar(footer, "ft", "", helpFooter+" (short)")
+
+	groupBy := flags.String("group-by", "", helpGroupBy)
+	flags.StringVar(groupBy, "gb", "", helpGroupBy+" (short)")
+
+	aggregates := flags.String("agg", "", helpAggregates)
+	flags.StringVar(aggregates, "ag", "", helpAggregates+" (short)")
+
+	rename := flags.String("rename", "", helpRename)
+	flags.StringVar(rename, "rn", "", helpRename+" (short)")
+
+	addColumn := flags.String("add-column", "", helpAddColumn)
+	flags.StringVar(addColumn, "ac", "", helpAddColumn+" (short)")
+
+	jsonTypes := flags.String("json-types", "", helpJSONTypes)
+	flags.StringVar(jsonTypes, "jt", "", helpJSONTypes+" (short)")
+
+	jsonSchema := flags.String("json-schema", "", helpJSONSchema)
+	flags.StringVar(jsonSchema, "js", "", helpJSONSchema+" (short)")
+
+	profile := flags.String(configProfileKey, "", helpProfile)
+	flags.StringVar(profile, "p", "", helpProfile+" (short)")
+
+	showConfig := flags.Bool("show-config", false, helpShowConfig)
+	flags.BoolVar(showConfig, "sc", false, helpShowConfig+" (short)")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return fmt.Errorf(errorWrapFormat, err)
+		}
+
+		// the flag set already printed the error and the usage.
+		return fmt.Errorf("%w, %w", ErrInvalidFlag, err)
+	}
+
+	profileName := *profile
+	if profileName == "" {
+		profileName = getenv(configEnvName(configProfileKey))
+	}
+	cfg, err := loadUserConfig(configPath(getenv))
+	if err != nil {
+		return err
+	}
+	values, err := applyUserConfig(flags, cfg, profileName, getenv)
+	if err != nil {
+		return err
+	}
+	// the input file argument is not a column, config columns still apply.
+	args, fileArgs := flags.Args(), []string(nil)
+	piped, err := pipedInput(stdin)
+	if err != nil {
+		return err
+	}
+	if !piped && len(args) > 0 {
+		fileArgs, args = args[:1], args[1:]
+	}
+	args, columns := configColumns(args, cfg, profileName, getenv)
+	if *showConfig {
+		return writeConfig(stdout, cfg.path, profileName, append(values, columns))
+	}
+
+	outputOption := WithOutputWriter(nopWriteCloser{stdout})
+	if *output != defaultOutput {
+		outputOption = WithOutput(*output)
 	}
 
 	tbl, err := New(
-		WithArgs(flag.Args()),
-		WithOutput(*output),
+		WithContext(ctx),
+		WithEnv(env),
+		WithArgs(append(fileArgs, args...)),
+		WithInput(stdin),
+		outputOption,
+		WithErrOutput(stderr),
 		WithDisplayVersion(*version),
 		WithReadInputFunc(readInput),
 		WithLineDelimiter(*lineDelimiterChar),
 		WithFieldDelimiter(*fieldDelimiterChar),
+		WithFieldDelimiterRegex(*fieldDelimiterRegex),
 		WithNoSeparateRows(*noSeparateRows),
 		WithNoDrawBorder(*noBorders),
 		WithNoHeaders(*noHeaders),
 		WithFilterIndexes(*filterIndexes),
 		WithJSONOutput(*jsonOutput),
+		WithFormat(*format),
+		WithStyle(*style),
+		WithTheme(*theme),
+		WithInputFormat(*inputFormat),
+		WithFixedWidths(*widths),
+		WithColor(*color),
+		WithAlign(*align),
+		WithMaxWidth(*maxWidth),
+		WithWidthMode(*widthMode),
+		WithFooter(*footer),
+		WithGroupBy(*groupBy),
+		WithAggregates(*aggregates),
+		WithRenames(*rename),
+		WithAddColumns(*addColumn),
+		WithJSONTypes(*jsonTypes),
+		WithJSONSchema(*jsonSchema),
+		WithRawSplit(*rawSplit),
+		WithSort(*sortSpec),
+		WithWhere(*where),
+		WithStream(*stream),
+		WithView(*view),
+		WithStreamWindow(*streamWindow),
 	)
 	if err != nil {
 		return err
@@ -901,7 +1916,7 @@ func Run() error {
 	}
 
 	if *output != defaultOutput {
-		fmt.Fprintf(flag.CommandLine.Output(), "result saved to: %s\n", *output)
+		fmt.Fprintf(stderr, "result saved to: %s\n", *output)
 		defer func() { _ = tbl.Output.Close() }()
 	}
 
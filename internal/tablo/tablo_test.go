@@ -2,12 +2,14 @@ package tablo_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -22,10 +24,6 @@ func (s *BytesWriteCloser) Close() error {
 	return nil
 }
 
-func (s *BytesWriteCloser) chomp() []byte {
-	return bytes.TrimSuffix(s.Bytes(), []byte("\n"))
-}
-
 func (s *BytesWriteCloser) nonStdinValue() []byte {
 	firstLineBreakIndex := bytes.IndexByte(s.Bytes(), '\n')
 	if firstLineBreakIndex > 0 {
@@ -35,8 +33,22 @@ func (s *BytesWriteCloser) nonStdinValue() []byte {
 	return s.Bytes()
 }
 
-func resetFlags() {
-	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+// noConfig keeps the developer's config file out of the command tests.
+var noConfig = []string{"TABLO_CONFIG=/nonexistent"}
+
+// runWith runs the command with env as its environment and an empty stdin
+// that is not a pipe, like an interactive shell.
+func runWith(t *testing.T, env []string, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+
+	stdin, err := os.Open(os.DevNull)
+	assert.NoError(t, err)
+	defer func() { _ = stdin.Close() }()
+
+	var out, errOut bytes.Buffer
+	err = tablo.RunWith(context.Background(), append([]string{"tablo"}, args...), stdin, &out, &errOut, env)
+
+	return out.String(), errOut.String(), err
 }
 
 func TestTablo_New_No_Options(t *testing.T) {
@@ -128,29 +140,67 @@ func TestTablo_New_WithFieldDelimiter_EmptyString(t *testing.T) {
 	assert.NotNil(t, tbl)
 	assert.NoError(t, err)
 }
+func TestTablo_New_WithFieldDelimiter_Escapes(t *testing.T) {
+	tests := []struct {
+		name      string
+		delimiter string
+		rune      rune
+		separator string
+	}{
+		{"single character", ";", ';', ""},
+		{"hex escape", `\x7c`, '|', ""},
+		{"unicode escape", `\u00a6`, '¦', ""},
+		{"multi character", "::", 0, "::"},
+		{"multi character with escape", `\t|\t`, 0, "\t|\t"},
+		{"nul", `\0`, 0, "\x00"},
+	}
 
-func TestTablo_GetVersion(t *testing.T) {
-	oldStdout := os.Stdout
-	defer func() { os.Stdout = oldStdout }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbl, err := tablo.New(tablo.WithFieldDelimiter(tt.delimiter))
 
-	r, w, err := os.Pipe()
-	assert.NoError(t, err)
-	os.Stderr = w
+			assert.NoError(t, err)
+			assert.Equal(t, tt.rune, tbl.FieldDelimiter)
+			assert.Equal(t, tt.separator, tbl.FieldSeparator)
+		})
+	}
+}
 
-	os.Args = []string{"tablo", "-version"}
-	resetFlags()
+func TestTablo_New_WithFieldDelimiter_InvalidEscape(t *testing.T) {
+	for _, delimiter := range []string{`\q`, `\x4`, `\uzzzz`} {
+		tbl, err := tablo.New(tablo.WithFieldDelimiter(delimiter))
 
-	output := new(BytesWriteCloser)
+		assert.Nil(t, tbl)
+		assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+	}
+}
 
-	err = tablo.Run()
-	assert.NoError(t, err)
+func TestTablo_New_WithFieldDelimiterRegex_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []tablo.Option
+	}{
+		{"syntax error", []tablo.Option{tablo.WithFieldDelimiterRegex(`(`)}},
+		{"matches empty string", []tablo.Option{tablo.WithFieldDelimiterRegex(`\s*`)}},
+		{"with field delimiter", []tablo.Option{tablo.WithFieldDelimiter("|"), tablo.WithFieldDelimiterRegex(`\|`)}},
+		{"before field delimiter", []tablo.Option{tablo.WithFieldDelimiterRegex(`\|`), tablo.WithFieldDelimiter("|")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbl, err := tablo.New(tt.options...)
 
-	_ = w.Close()
-	os.Stdout = oldStdout
+			assert.Nil(t, tbl)
+			assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+		})
+	}
+}
 
-	_, _ = output.ReadFrom(r)
+func TestTablo_GetVersion(t *testing.T) {
+	_, stderr, err := runWith(t, noConfig, "-version")
+	assert.NoError(t, err)
 
-	assert.Equal(t, tablo.Version, string(output.chomp()))
+	assert.Equal(t, tablo.Version+"\n", stderr)
 }
 
 func TestTablo_Tabelize_SingleString(t *testing.T) {
@@ -217,6 +267,69 @@ func TestTablo_Tabelize_SingleString_With_R_LineDelimiter(t *testing.T) {
 	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
 }
 
+func TestTablo_Tabelize_LineDelimiters(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		delimiter string
+	}{
+		{"crlf", "name,age\r\nvigo,42\r\n", "\n"},
+		{"carriage return", "name,age\rvigo,42\r", "\n"},
+		{"nul", "name,age\x00vigo,42\x00", `\0`},
+		{"multi character", "name,age;;vigo,42;;", ";;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := new(BytesWriteCloser)
+
+			tbl, err := tablo.New(
+				tablo.WithOutputWriter(output),
+				tablo.WithLineDelimiter(tt.delimiter),
+				tablo.WithArgs([]string{"age"}),
+				tablo.WithInput(strings.NewReader(tt.input)),
+			)
+
+			assert.NoError(t, err)
+			assert.NotNil(t, tbl)
+
+			err = tbl.Tabelize()
+			assert.NoError(t, err)
+			expectedOutput := "┌─────┐\n│ age │\n├─────┤\n│  42 │\n└─────┘\n"
+			assert.Equal(t, expectedOutput, output.String())
+		})
+	}
+}
+
+func TestTablo_Tabelize_NULLineDelimiter_CSVOutput(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithLineDelimiter(`\0`),
+		tablo.WithFormat("csv"),
+		tablo.WithNoHeaders(true),
+		tablo.WithFilterIndexes("1"),
+		tablo.WithInput(strings.NewReader("./a b.txt\x00./c\nd.txt\x00./a,b\x00./say \"hi\"\x00")),
+	)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, tbl)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+	assert.Equal(t, "./a b.txt\x00./c\nd.txt\x00./a,b\x00./say \"hi\"\x00", output.String())
+}
+
+func TestTablo_New_WithLineDelimiter_InvalidEscape(t *testing.T) {
+	tbl, err := tablo.New(
+		tablo.WithLineDelimiter(`\q`),
+	)
+
+	assert.Nil(t, tbl)
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
 func TestTablo_Tabelize_SingleString_No_Borders(t *testing.T) {
 	input := bytes.NewBufferString("hello\nworld")
 	output := new(BytesWriteCloser)
@@ -376,6 +489,86 @@ func TestTablo_Tabelize_WithFieldDelimiter_FORM_FIELD(t *testing.T) {
 	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
 }
 
+func TestTablo_Tabelize_WithFieldDelimiter_MultiCharacter(t *testing.T) {
+	input := bytes.NewBufferString("name | notes\nvigo | \"a | b\"\n")
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter(" | "),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input.String(), nil
+		}),
+	)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, tbl)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌──────┬───────┐
+│ name │ notes │
+├──────┼───────┤
+│ vigo │ a | b │
+└──────┴───────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithFieldDelimiter_NUL(t *testing.T) {
+	input := bytes.NewBufferString("hello4\x00world4\n")
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter(`\0`),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input.String(), nil
+		}),
+	)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, tbl)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := "┌────────┬────────┐\n│ hello4 │ world4 │\n└────────┴────────┘\n"
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithFieldDelimiterRegex(t *testing.T) {
+	input := bytes.NewBufferString("name|age\nvigo  |  42\njohn| 33\n")
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiterRegex(`\s*\|\s*`),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithNoSeparateRows(true),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input.String(), nil
+		}),
+	)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, tbl)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌──────┬─────┐
+│ name │ age │
+│ vigo │  42 │
+│ john │  33 │
+└──────┴─────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
 func TestTablo_Tabelize_WithFieldDelimiter_WithFilterIndexes(t *testing.T) {
 	input := bytes.NewBufferString("hello|world\n")
 	output := new(BytesWriteCloser)
@@ -413,10 +606,16 @@ func TestTablo_Tabelize_WithFieldDelimiter_WithFilterIndexes_Invalid(t *testing.
 			return input.String(), nil
 		}),
 	)
+	assert.NoError(t, err)
 
-	assert.Error(t, err)
+	err = tbl.Tabelize()
 	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
-	assert.Nil(t, tbl)
+
+	for _, indexes := range []string{"0", "2,", "3-1", "/[/"} {
+		tbl, err = tablo.New(tablo.WithFilterIndexes(indexes))
+		assert.ErrorIs(t, err, tablo.ErrInvalidValue, indexes)
+		assert.Nil(t, tbl)
+	}
 }
 
 func TestTablo_Tabelize_WithFieldDelimiter_Column_Selection(t *testing.T) {
@@ -479,15 +678,8 @@ func TestTablo_Tabelize_WithFieldDelimiter_Wrong_Column_Selection(t *testing.T)
 	assert.NotNil(t, tbl)
 
 	err = tbl.Tabelize()
-	assert.NoError(t, err)
-
-	expectedOutput := `┌─────────┬─────────┐
-│ header1 │ header2 │
-├─────────┼─────────┤
-│ foo     │ bar     │
-└─────────┴─────────┘
-`
-	assert.Equal(t, expectedOutput, output.String())
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+	assert.Empty(t, output.String())
 }
 
 func TestTablo_Tabelize_WithFieldDelimiter_Wrong_Column_Selection_WithNoHeaders(t *testing.T) {
@@ -518,10 +710,8 @@ func TestTablo_Tabelize_WithFieldDelimiter_Wrong_Column_Selection_WithNoHeaders(
 	assert.NotNil(t, tbl)
 
 	err = tbl.Tabelize()
-	assert.NoError(t, err)
-
-	expectedOutput := "┌─────┬─────┐\n│ foo │ bar │\n└─────┴─────┘\n"
-	assert.Equal(t, expectedOutput, output.String())
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+	assert.Empty(t, output.String())
 }
 
 func TestTablo_Tabelize_FilterIndexesIgnoreArgsForHeaders(t *testing.T) {
@@ -554,7 +744,7 @@ func TestTablo_Tabelize_FilterIndexesIgnoreArgsForHeaders(t *testing.T) {
 	err = tbl.Tabelize()
 	assert.NoError(t, err)
 
-	expectedOutput := "┌─────┐\n│ age │\n├─────┤\n│ 42  │\n└─────┘\n"
+	expectedOutput := "┌─────┐\n│ age │\n├─────┤\n│  42 │\n└─────┘\n"
 	assert.Equal(t, expectedOutput, output.String())
 }
 
@@ -580,7 +770,7 @@ superset-superset-worker           latest    d25cbcc60691   2 weeks ago     958M
 	assert.NoError(t, err)
 
 	expectedOutput := `┌───────────────────────────────┬────────┬──────────────┬─────────────┬───────┐
-│ REPOSITORY                    │ TAG    │ IMAGE ID     │ CREATED     │ SIZE  │
+│ REPOSITORY                    │ TAG    │ IMAGE ID     │ CREATED     │  SIZE │
 ├───────────────────────────────┼────────┼──────────────┼─────────────┼───────┤
 │ superset-superset-worker-beat │ latest │ 3292fc2e6758 │ 2 weeks ago │ 958MB │
 ├───────────────────────────────┼────────┼──────────────┼─────────────┼───────┤
@@ -827,11 +1017,11 @@ func TestTablo_Tabelize_CSV_WithQuotedUnicodeHeaders_AndNoHeaders_SkipsHeaderRow
 	err = tbl.Tabelize()
 	assert.NoError(t, err)
 
-	expectedOutput := `┌────────┬──────────────────┬─────────────┬──────────────────┐
-│ 999721 │ "Filiz Yenilmez" │ 23795235860 │ Muhasebe         │
-├────────┼──────────────────┼─────────────┼──────────────────┤
-│ 999722 │ "Ali Veli"       │ 12345678901 │ İnsan Kaynakları │
-└────────┴──────────────────┴─────────────┴──────────────────┘
+	expectedOutput := `┌────────┬────────────────┬─────────────┬──────────────────┐
+│ 999721 │ Filiz Yenilmez │ 23795235860 │ Muhasebe         │
+├────────┼────────────────┼─────────────┼──────────────────┤
+│ 999722 │ Ali Veli       │ 12345678901 │ İnsan Kaynakları │
+└────────┴────────────────┴─────────────┴──────────────────┘
 `
 	assert.Equal(t, expectedOutput, output.String())
 }
@@ -867,190 +1057,1292 @@ func TestTablo_Tabelize_CSV_AutoDetectsDelimiter_WithNoHeaders(t *testing.T) {
 	err = tbl.Tabelize()
 	assert.NoError(t, err)
 
-	expectedOutput := `┌────────┬──────────────────┬─────────────┬──────────────────┐
-│ 999721 │ "Filiz Yenilmez" │ 23795235860 │ Muhasebe         │
-├────────┼──────────────────┼─────────────┼──────────────────┤
-│ 999722 │ "Ali Veli"       │ 12345678901 │ İnsan Kaynakları │
-└────────┴──────────────────┴─────────────┴──────────────────┘
+	expectedOutput := `┌────────┬────────────────┬─────────────┬──────────────────┐
+│ 999721 │ Filiz Yenilmez │ 23795235860 │ Muhasebe         │
+├────────┼────────────────┼─────────────┼──────────────────┤
+│ 999722 │ Ali Veli       │ 12345678901 │ İnsan Kaynakları │
+└────────┴────────────────┴─────────────┴──────────────────┘
 `
 	assert.Equal(t, expectedOutput, output.String())
 }
 
-func TestTablo_Run_Returns_Error(t *testing.T) {
-	os.Args = []string{"tablo", "-l", ""}
-	resetFlags()
-
-	oldStdout := os.Stdout
-	_, w, _ := os.Pipe()
-	os.Stdout = w
+func TestTablo_Tabelize_CSV_QuotedFields_WithEmbeddedDelimitersAndLineBreaks(t *testing.T) {
+	input := bytes.NewBufferString(`name,notes
+"Smith, John","said ""hi""
+and left"
+vigo,plain
+`)
+	output := new(BytesWriteCloser)
 
-	err := tablo.Run()
-	assert.Error(t, err)
-	_ = w.Close()
-	os.Stdout = oldStdout
-}
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithNoSeparateRows(true),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input.String(), nil
+		}),
+	)
 
-func TestTablo_Run_Read_Input_From_File(t *testing.T) {
-	tmpFile, err := os.CreateTemp("", "test.txt")
 	assert.NoError(t, err)
-	defer func() { _ = os.Remove(tmpFile.Name()) }()
-	content := `SCENE       SCENER            GROUP
-C64         vigo              Bronx^zOMBiE BoYS^tRSI
-AMIGA       turbo             Bronx^zOMBiE BoYS
-AMIGA       move              Bronx^zOMBiE BoYS
-C64         street tuff       tRSI`
+	assert.NotNil(t, tbl)
 
-	_, err = tmpFile.WriteString(content)
+	err = tbl.Tabelize()
 	assert.NoError(t, err)
-	_ = tmpFile.Close()
-
-	os.Args = []string{"tablo", "-n", tmpFile.Name()}
-	resetFlags()
 
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	err = tablo.Run()
-	assert.NoError(t, err)
-	_ = w.Close()
-	os.Stdout = oldStdout
+	expectedOutput := `┌─────────────┬───────────┐
+│ name        │ notes     │
+│ Smith, John │ said "hi" │
+│             │ and left  │
+│ vigo        │ plain     │
+└─────────────┴───────────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
 
+func TestTablo_Tabelize_CSV_RawSplit_KeepsQuotes(t *testing.T) {
+	input := bytes.NewBufferString(`1,"Smith, John"
+2,"Doe, Jane"
+`)
 	output := new(BytesWriteCloser)
-	_, _ = output.ReadFrom(r)
 
-	expectedOutput := `┌───────┬─────────────┬────────────────────────┐
-│ SCENE │ SCENER      │ GROUP                  │
-│ C64   │ vigo        │ Bronx^zOMBiE BoYS^tRSI │
-│ AMIGA │ turbo       │ Bronx^zOMBiE BoYS      │
-│ AMIGA │ move        │ Bronx^zOMBiE BoYS      │
-│ C64   │ street tuff │ tRSI                   │
-└───────┴─────────────┴────────────────────────┘
-`
-	assert.Equal(t, expectedOutput, output.String())
-}
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter(","),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithNoSeparateRows(true),
+		tablo.WithRawSplit(true),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input.String(), nil
+		}),
+	)
 
-func TestTablo_Run_Read_Input_From_File_Filter_Header(t *testing.T) {
-	tmpFile, err := os.CreateTemp("", "test.txt")
 	assert.NoError(t, err)
-	defer func() { _ = os.Remove(tmpFile.Name()) }()
-	content := `# comment
-SCENE       SCENER            GROUP
-C64         vigo              Bronx^zOMBiE BoYS^tRSI
-AMIGA       turbo             Bronx^zOMBiE BoYS
-AMIGA       move              Bronx^zOMBiE BoYS
-C64         street tuff       tRSI`
+	assert.NotNil(t, tbl)
 
-	_, err = tmpFile.WriteString(content)
+	err = tbl.Tabelize()
 	assert.NoError(t, err)
-	_ = tmpFile.Close()
 
-	os.Args = []string{"tablo", "-n", tmpFile.Name(), "SCENE"}
-	resetFlags()
+	expectedOutput := `┌───┬────────┬────────┐
+│ 1 │ "Smith │  John" │
+│ 2 │ "Doe   │  Jane" │
+└───┴────────┴────────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
 
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+func TestTablo_Tabelize_Sort_TableAndJSON(t *testing.T) {
+	input := "name|age\nvigo|42\njohn|7\njane|100\n"
 
-	err = tablo.Run()
+	output := new(BytesWriteCloser)
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithNoSeparateRows(true),
+		tablo.WithSort("AGE:desc"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
 	assert.NoError(t, err)
-	_ = w.Close()
-	os.Stdout = oldStdout
 
-	output := new(BytesWriteCloser)
-	_, _ = output.ReadFrom(r)
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
 
-	expectedOutput := `┌───────┐
-│ SCENE │
-├───────┤
-│ C64   │
-│ AMIGA │
-│ AMIGA │
-│ C64   │
-└───────┘
+	expectedOutput := `┌──────┬─────┐
+│ name │ age │
+│ jane │ 100 │
+│ vigo │  42 │
+│ john │   7 │
+└──────┴─────┘
 `
-	assert.Equal(t, expectedOutput, output.String())
-}
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
 
-func TestTablo_Run_Read_Input_From_File_TAB_as_LineDelimiter(t *testing.T) {
-	tmpFile, err := os.CreateTemp("", "test.txt")
+	jsonOutput := new(BytesWriteCloser)
+	tbl, err = tablo.New(
+		tablo.WithOutputWriter(jsonOutput),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithJSONOutput(true),
+		tablo.WithSort("1"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
 	assert.NoError(t, err)
-	defer func() { _ = os.Remove(tmpFile.Name()) }()
-	content := "hello\tworld"
 
-	_, err = tmpFile.WriteString(content)
+	err = tbl.Tabelize()
 	assert.NoError(t, err)
-	_ = tmpFile.Close()
 
-	os.Args = []string{"tablo", "-n", "-l", "\t", tmpFile.Name()}
-	resetFlags()
+	expectedJSON := `[
+  {
+    "name": "jane",
+    "age": "100"
+  },
+  {
+    "name": "john",
+    "age": "7"
+  },
+  {
+    "name": "vigo",
+    "age": "42"
+  }
+]
+`
+	assert.Equal(t, expectedJSON, string(jsonOutput.nonStdinValue()))
+}
 
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+func TestTablo_New_WithSort_Invalid(t *testing.T) {
+	tbl, err := tablo.New(
+		tablo.WithSort("name,,age"),
+	)
 
-	err = tablo.Run()
-	assert.NoError(t, err)
-	_ = w.Close()
-	os.Stdout = oldStdout
+	assert.Nil(t, tbl)
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
 
+func TestTablo_Tabelize_Where_Docker_Images(t *testing.T) {
+	input := `REPOSITORY   TAG      IMAGE ID       CREATED        SIZE
+vigo/web     latest   911f45e85b68   22 hours ago   12.7MB
+vigo/db      16       b72784c93710   3 weeks ago    412MB
+vigo/cache   7        c0ffee000000   2 days ago     40.1MB
+`
 	output := new(BytesWriteCloser)
-	_, _ = output.ReadFrom(r)
 
-	expectedOutput := `┌───────┐
-│ hello │
-│ world │
-└───────┘
-`
-	assert.Equal(t, expectedOutput, output.String())
-}
+	oldIsNamedPipe := tablo.IsNamedPipe
+	oldIsCharDevice := tablo.IsCharDevice
+	tablo.IsNamedPipe = func(_ os.FileInfo) bool { return true }
+	tablo.IsCharDevice = func(_ os.FileInfo) bool { return false }
+	defer func() {
+		tablo.IsNamedPipe = oldIsNamedPipe
+		tablo.IsCharDevice = oldIsCharDevice
+	}()
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithNoSeparateRows(true),
+		tablo.WithWhere(`SIZE > 20mb and CREATED < 1w`),
+		tablo.WithArgs([]string{"REPOSITORY", "SIZE"}),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌────────────┬────────┐
+│ REPOSITORY │   SIZE │
+├────────────┼────────┤
+│ vigo/cache │ 40.1MB │
+└────────────┴────────┘
+`
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestTablo_Tabelize_Where_UnknownColumn(t *testing.T) {
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(new(BytesWriteCloser)),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithWhere("missing = 1"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "name|age\nvigo|42\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_New_WithWhere_ParseError(t *testing.T) {
+	tbl, err := tablo.New(
+		tablo.WithWhere("name = "),
+	)
+
+	assert.Nil(t, tbl)
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_Tabelize_Format_Markdown_WithSelectedColumns(t *testing.T) {
+	input := "name|age|city\nvigo|42|istanbul\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithFormat("md"),
+		tablo.WithFilterIndexes("3,1"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `| city | name |
+| --- | --- |
+| istanbul | vigo |
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_Format_FilterIndexes_NoHeaders(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"csv", "vigo,42\njohn,7\n"},
+		{"tsv", "vigo\t42\njohn\t7\n"},
+		{"markdown", "| vigo | 42 |\n| john | 7 |\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			output := new(BytesWriteCloser)
+
+			tbl, err := tablo.New(
+				tablo.WithOutputWriter(output),
+				tablo.WithFieldDelimiter(","),
+				tablo.WithLineDelimiter("\n"),
+				tablo.WithFormat(tt.format),
+				tablo.WithFilterIndexes("1,2"),
+				tablo.WithNoHeaders(true),
+				tablo.WithInput(strings.NewReader("name,age\nvigo,42\njohn,7\n")),
+			)
+			assert.NoError(t, err)
+
+			err = tbl.Tabelize()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, output.String())
+		})
+	}
+}
+
+func TestTablo_New_WithFormat_JSONEnablesJSONOutput(t *testing.T) {
+	tbl, err := tablo.New(
+		tablo.WithFormat("json"),
+	)
+
+	assert.NoError(t, err)
+	assert.True(t, tbl.JSONOutput)
+}
+
+func TestTablo_New_WithFormat_Invalid(t *testing.T) {
+	tbl, err := tablo.New(
+		tablo.WithFormat("yaml"),
+	)
+
+	assert.Nil(t, tbl)
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_Tabelize_WithStyle_ASCII(t *testing.T) {
+	input := "name|age\nvigo|42\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithStyle("ascii"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `+------+-----+
+| name | age |
++------+-----+
+| vigo |  42 |
++------+-----+
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_New_WithStyle_Invalid(t *testing.T) {
+	tbl, err := tablo.New(
+		tablo.WithStyle("fancy"),
+	)
+
+	assert.Nil(t, tbl)
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_Tabelize_JSONInput_Sniffed(t *testing.T) {
+	input := `[{"name": "web", "status": {"phase": "Running"}}, {"name": "db", "status": {"phase": "Pending"}}]`
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithWhere(`status.phase = Running`),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌──────┬──────────────┐
+│ name │ status.phase │
+├──────┼──────────────┤
+│ web  │ Running      │
+└──────┴──────────────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_YAMLInput_JSONOutput(t *testing.T) {
+	input := "- name: vigo\n  langs: [go, ruby]\n- name: john\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithInputFormat("yml"),
+		tablo.WithJSONOutput(true),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `[
+  {
+    "name": "vigo",
+    "langs": "go,ruby"
+  },
+  {
+    "name": "john",
+    "langs": ""
+  }
+]
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_New_WithInputFormat_Invalid(t *testing.T) {
+	tbl, err := tablo.New(
+		tablo.WithInputFormat("xml"),
+	)
+
+	assert.Nil(t, tbl)
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_Tabelize_JSONTypes_Infer(t *testing.T) {
+	input := "name|uid|admin\nroot|0|true\nvigo|501|false\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithJSONOutput(true),
+		tablo.WithJSONTypes("infer"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `[
+  {
+    "name": "root",
+    "uid": 0,
+    "admin": true
+  },
+  {
+    "name": "vigo",
+    "uid": 501,
+    "admin": false
+  }
+]
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_JSONSchema_Mismatch(t *testing.T) {
+	input := "name|uid\nroot|0\nvigo|n/a\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithJSONOutput(true),
+		tablo.WithJSONSchema("uid:int"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, tablo.JSONTypesSchema, tbl.JSONTypes)
+
+	err = tbl.Tabelize()
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+	assert.ErrorContains(t, err, `row 2, column "uid": "n/a" is not an int`)
+}
+
+func TestTablo_New_JSONSchema_WithOtherJSONTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []tablo.Option
+	}{
+		{"types first", []tablo.Option{tablo.WithJSONTypes("strings"), tablo.WithJSONSchema("uid:int")}},
+		{"schema first", []tablo.Option{tablo.WithJSONSchema("uid:int"), tablo.WithJSONTypes("infer")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbl, err := tablo.New(tt.options...)
+
+			assert.Nil(t, tbl)
+			assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+		})
+	}
+
+	tbl, err := tablo.New(tablo.WithJSONSchema("uid:int"), tablo.WithJSONTypes("schema"))
+	assert.NoError(t, err)
+	assert.Equal(t, tablo.JSONTypesSchema, tbl.JSONTypes)
+}
+
+func TestTablo_Tabelize_WithAlign(t *testing.T) {
+	input := "name|size|count\nweb|10MB|3\ndatabase|1.2GB|12\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithNoSeparateRows(true),
+		tablo.WithAlign("name:center,3:left"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌──────────┬───────┬───────┐
+│   name   │  size │ count │
+│    web   │  10MB │ 3     │
+│ database │ 1.2GB │ 12    │
+└──────────┴───────┴───────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithAlign_UnknownColumn(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithAlign("age:right"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "name|size\nweb|10MB\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_Tabelize_WithMaxWidth(t *testing.T) {
+	input := "hash|message\nabc123|Fix the race condition in the file watcher\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithMaxWidth("message:20"),
+		tablo.WithWidthMode("ellipsis"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌────────┬──────────────────────┐
+│ hash   │ message              │
+├────────┼──────────────────────┤
+│ abc123 │ Fix the race condit… │
+└────────┴──────────────────────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_FitsTerminalWidth(t *testing.T) {
+	oldTerminalWidth := tablo.TerminalWidth
+	defer func() { tablo.TerminalWidth = oldTerminalWidth }()
+	tablo.TerminalWidth = func(io.Writer) int { return 30 }
+
+	input := "hash|message\nabc123|Fix the race condition in the file watcher\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌────────┬───────────────────┐
+│ hash   │ message           │
+├────────┼───────────────────┤
+│ abc123 │ Fix the race      │
+│        │ condition in the  │
+│        │ file watcher      │
+└────────┴───────────────────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_New_WithWidthMode_Invalid(t *testing.T) {
+	_, err := tablo.New(tablo.WithWidthMode("clip"))
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+
+	_, err = tablo.New(tablo.WithMaxWidth("message:wide"))
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_Tabelize_WithFooter(t *testing.T) {
+	input := "name|size|layers\nweb|12.7MB|3\ndb|1.2GB|7\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithNoSeparateRows(true),
+		tablo.WithFooter("size:sum,name:count,layers:avg"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌──────┬────────┬────────┐
+│ name │   size │ layers │
+│ web  │ 12.7MB │      3 │
+│ db   │  1.2GB │      7 │
+├──────┼────────┼────────┤
+│ 2    │  1.2GB │      5 │
+└──────┴────────┴────────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithFooter_JSON(t *testing.T) {
+	input := "name|size|layers\nweb|12.7MB|3\ndb|1.2GB|7\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithJSONOutput(true),
+		tablo.WithJSONTypes("infer"),
+		tablo.WithFooter("layers:sum,size:max"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `{
+  "rows": [
+    {
+      "name": "web",
+      "size": "12.7MB",
+      "layers": 3
+    },
+    {
+      "name": "db",
+      "size": "1.2GB",
+      "layers": 7
+    }
+  ],
+  "_footer": {
+    "layers": 10,
+    "size": "1.2GB"
+  }
+}
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithGroupBy(t *testing.T) {
+	input := "repository|tag|size\nweb|latest|12.7MB\ndb|15|1.2GB\nweb|1.0|10MB\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithNoSeparateRows(true),
+		tablo.WithGroupBy("repository"),
+		tablo.WithAggregates("size:sum,tag:count"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌────────────┬───────────┬────────────┐
+│ repository │ sum(size) │ count(tag) │
+├────────────┼───────────┼────────────┤
+│ web        │    22.7MB │          2 │
+│ db         │     1.2GB │          1 │
+└────────────┴───────────┴────────────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithGroupBy_JSON(t *testing.T) {
+	input := "repository|tag|size\nweb|latest|12.7MB\ndb|15|1.2GB\nweb|1.0|10MB\n"
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithJSONOutput(true),
+		tablo.WithGroupBy("repository"),
+		tablo.WithAggregates("tag:count"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `[
+  {
+    "repository": "web",
+    "count(tag)": "2"
+  },
+  {
+    "repository": "db",
+    "count(tag)": "1"
+  }
+]
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithFilterIndexes_Selectors(t *testing.T) {
+	input := "NAME|IMAGE|IMAGE ID|PORTS|STATUS\nweb|nginx|abc|80/tcp|up\n"
+	tests := []struct {
+		indexes  string
+		expected string
+	}{
+		{indexes: "2-3", expected: "IMAGE,IMAGE ID\nnginx,abc\n"},
+		{indexes: "4-", expected: "PORTS,STATUS\n80/tcp,up\n"},
+		{indexes: "-1,name", expected: "STATUS,NAME\nup,web\n"},
+		{indexes: "!ports,!/^IMAGE/", expected: "NAME,STATUS\nweb,up\n"},
+		{indexes: "image*", expected: "IMAGE,IMAGE ID\nnginx,abc\n"},
+	}
+
+	for _, tt := range tests {
+		output := new(BytesWriteCloser)
+		tbl, err := tablo.New(
+			tablo.WithOutputWriter(output),
+			tablo.WithFieldDelimiter("|"),
+			tablo.WithLineDelimiter("\n"),
+			tablo.WithFormat("csv"),
+			tablo.WithFilterIndexes(tt.indexes),
+			tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+				return input, nil
+			}),
+		)
+		assert.NoError(t, err)
+
+		err = tbl.Tabelize()
+		assert.NoError(t, err, tt.indexes)
+		assert.Equal(t, tt.expected, string(output.nonStdinValue()), tt.indexes)
+	}
+}
+
+func TestTablo_Tabelize_WithFilterIndexes_OutOfRange(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithFilterIndexes("1,4"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "a|b|c\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+	assert.Contains(t, err.Error(), "out of range")
+}
+
+func TestTablo_Tabelize_WithColumnSelectorArgs(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	oldIsNamedPipe := tablo.IsNamedPipe
+	oldIsCharDevice := tablo.IsCharDevice
+	tablo.IsNamedPipe = func(_ os.FileInfo) bool { return true }
+	tablo.IsCharDevice = func(_ os.FileInfo) bool { return false }
+	defer func() {
+		tablo.IsNamedPipe = oldIsNamedPipe
+		tablo.IsCharDevice = oldIsCharDevice
+	}()
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithFormat("csv"),
+		tablo.WithArgs([]string{"/^IMAGE/", "1"}),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "NAME|IMAGE|IMAGE ID|PORTS\nweb|nginx|abc|80/tcp\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+	assert.Equal(t, "IMAGE,IMAGE ID,NAME\nnginx,abc,web\n", output.String())
+}
+
+func TestTablo_Tabelize_WithRenames(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithFilterIndexes("size,image id"),
+		tablo.WithRenames("image id=id,1=size_mb"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "NAME|IMAGE ID|SIZE\nweb|abc|12\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌─────────┬─────┐
+│ size_mb │ id  │
+├─────────┼─────┤
+│      12 │ abc │
+└─────────┴─────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithRenames_JSON(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithJSONOutput(true),
+		tablo.WithRenames("IMAGE ID=image_id,SIZE=size_mb"),
+		tablo.WithFooter("size:sum"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "NAME|IMAGE ID|SIZE\nweb|abc|12\ndb|def|3\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `{
+  "rows": [
+    {
+      "NAME": "web",
+      "image_id": "abc",
+      "size_mb": "12"
+    },
+    {
+      "NAME": "db",
+      "image_id": "def",
+      "size_mb": "3"
+    }
+  ],
+  "_footer": {
+    "size_mb": "15"
+  }
+}
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithRenames_Invalid(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithRenames("missing=x"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "NAME|SIZE\nweb|12\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+
+	_, err = tablo.New(tablo.WithRenames("NAME"))
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_Tabelize_WithAddColumns(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithFilterIndexes("1"),
+		tablo.WithAddColumns(`image=NAME & ":" & TAG; mb=bytes(SIZE) / 1000000`),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "NAME|TAG|SIZE\nweb|latest|12.7MB\ndb|15|438MB\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌──────┬────────────┬──────┐
+│ NAME │ image      │   mb │
+├──────┼────────────┼──────┤
+│ web  │ web:latest │ 12.7 │
+├──────┼────────────┼──────┤
+│ db   │ db:15      │  438 │
+└──────┴────────────┴──────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithAddColumns_JSON(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithJSONOutput(true),
+		tablo.WithJSONTypes("infer"),
+		tablo.WithAddColumns("total=PRICE * QTY"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "ITEM|PRICE|QTY\npen|1.5|4\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `[
+  {
+    "ITEM": "pen",
+    "PRICE": 1.5,
+    "QTY": 4,
+    "total": 6
+  }
+]
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithAddColumns_Invalid(t *testing.T) {
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(new(BytesWriteCloser)),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithAddColumns("x=MISSING & 1"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "NAME|SIZE\nweb|12\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+
+	_, err = tablo.New(tablo.WithAddColumns("x=upper("))
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_Tabelize_WithInputFormat_Fixed(t *testing.T) {
+	input := `CONTAINER ID   IMAGE          STATUS       PORTS                  NAMES
+4f2c1d9a8b7e   nginx:latest   Up 2 hours   0.0.0.0:8080->80/tcp   web
+9a8b7c6d5e4f   postgres:15    Up 3 days                           db
+`
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithInputFormat("fixed"),
+		tablo.WithFormat("csv"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return input, nil
+		}),
+	)
+	assert.NoError(t, err)
 
-func TestTablo_Run_Read_Input_From_File_R_as_LineDelimiter(t *testing.T) {
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `CONTAINER ID,IMAGE,STATUS,PORTS,NAMES
+4f2c1d9a8b7e,nginx:latest,Up 2 hours,0.0.0.0:8080->80/tcp,web
+9a8b7c6d5e4f,postgres:15,Up 3 days,,db
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithFixedWidths(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithFixedWidths("5,3,*"),
+		tablo.WithFormat("csv"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "name age city\nvigo  42 new york\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+	assert.Equal(t, "name,age,city\nvigo,42,new york\n", string(output.nonStdinValue()))
+
+	tbl, err = tablo.New(
+		tablo.WithOutputWriter(new(BytesWriteCloser)),
+		tablo.WithInputFormat("json"),
+		tablo.WithFixedWidths("5"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "[]", nil
+		}),
+	)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, tbl.Tabelize(), tablo.ErrInvalidValue)
+
+	_, err = tablo.New(tablo.WithFixedWidths("5,0"))
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_Tabelize_WithColor(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithColor("always,STATUS~Exited=red,CPU>80=bold"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "NAME|STATUS|CPU\nweb|Up|90\ndb|Exited|10\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := "┌──────┬────────┬─────┐\n" +
+		"│ NAME │ STATUS │ CPU │\n" +
+		"├──────┼────────┼─────┤\n" +
+		"│ web  │ Up     │  \x1b[1m90\x1b[0m │\n" +
+		"├──────┼────────┼─────┤\n" +
+		"│ db   │ \x1b[31mExited\x1b[0m │  10 │\n" +
+		"└──────┴────────┴─────┘\n"
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Tabelize_WithColor_Disabled(t *testing.T) {
+	for _, spec := range []string{"never,STATUS~Up=green", "STATUS~Up=green"} {
+		output := new(BytesWriteCloser)
+
+		tbl, err := tablo.New(
+			tablo.WithOutputWriter(output),
+			tablo.WithFieldDelimiter("|"),
+			tablo.WithLineDelimiter("\n"),
+			tablo.WithColor(spec),
+			tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+				return "NAME|STATUS\nweb|Up\n", nil
+			}),
+		)
+		assert.NoError(t, err)
+
+		err = tbl.Tabelize()
+		assert.NoError(t, err)
+		assert.NotContains(t, output.String(), "\x1b[", spec)
+	}
+
+	_, err := tablo.New(tablo.WithColor("STATUS~Up=pink"))
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_Tabelize_WithTheme(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithNoSeparateRows(true),
+		tablo.WithTheme("light"),
+		tablo.WithColor("always"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "NAME|CPU\nweb|90\ndb|10\napi|5\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := "┌──────┬─────┐\n" +
+		"│\x1b[1;34m NAME \x1b[0m│\x1b[1;34m CPU \x1b[0m│\n" +
+		"│ web  │  90 │\n" +
+		"│\x1b[47m db   \x1b[0m│\x1b[47m  10 \x1b[0m│\n" +
+		"│ api  │   5 │\n" +
+		"└──────┴─────┘\n"
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+
+	output = new(BytesWriteCloser)
+	tbl, err = tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithTheme("dark"),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "NAME|CPU\nweb|90\ndb|10\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+	assert.NotContains(t, output.String(), "\x1b[")
+
+	_, err = tablo.New(tablo.WithTheme("neon"))
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestTablo_Tabelize_WithView_NotATerminal(t *testing.T) {
+	output := new(BytesWriteCloser)
+
+	tbl, err := tablo.New(
+		tablo.WithOutputWriter(output),
+		tablo.WithFieldDelimiter("|"),
+		tablo.WithLineDelimiter("\n"),
+		tablo.WithView(true),
+		tablo.WithReadInputFunc(func(_ io.Reader) (string, error) {
+			return "NAME|SIZE\nweb|10\n", nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	err = tbl.Tabelize()
+	assert.NoError(t, err)
+
+	expectedOutput := `┌──────┬──────┐
+│ NAME │ SIZE │
+├──────┼──────┤
+│ web  │   10 │
+└──────┴──────┘
+`
+	assert.Equal(t, expectedOutput, string(output.nonStdinValue()))
+}
+
+func TestTablo_Run_Returns_Error(t *testing.T) {
+	_, _, err := runWith(t, noConfig, "-l", "")
+	assert.Error(t, err)
+}
+
+func TestTablo_Run_Read_Input_From_File(t *testing.T) {
 	tmpFile, err := os.CreateTemp("", "test.txt")
 	assert.NoError(t, err)
 	defer func() { _ = os.Remove(tmpFile.Name()) }()
-	content := "hello\rworld"
+	content := `SCENE       SCENER            GROUP
+C64         vigo              Bronx^zOMBiE BoYS^tRSI
+AMIGA       turbo             Bronx^zOMBiE BoYS
+AMIGA       move              Bronx^zOMBiE BoYS
+C64         street tuff       tRSI`
+
+	_, err = tmpFile.WriteString(content)
+	assert.NoError(t, err)
+	_ = tmpFile.Close()
+
+	output, _, err := runWith(t, noConfig, "-n", tmpFile.Name())
+	assert.NoError(t, err)
+
+	expectedOutput := `┌───────┬─────────────┬────────────────────────┐
+│ SCENE │ SCENER      │ GROUP                  │
+│ C64   │ vigo        │ Bronx^zOMBiE BoYS^tRSI │
+│ AMIGA │ turbo       │ Bronx^zOMBiE BoYS      │
+│ AMIGA │ move        │ Bronx^zOMBiE BoYS      │
+│ C64   │ street tuff │ tRSI                   │
+└───────┴─────────────┴────────────────────────┘
+`
+	assert.Equal(t, expectedOutput, output)
+}
+
+func TestTablo_Run_Read_Input_From_File_WithProfile(t *testing.T) {
+	configDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(configDir, "tablo"), 0o700))
+	config := `no-separate-rows = true
+
+[profiles.passwd]
+field-delimiter-char = ":"
+filter-indexes = "1,7"
+`
+	err := os.WriteFile(filepath.Join(configDir, "tablo", "config"), []byte(config), 0o600)
+	assert.NoError(t, err)
+
+	inputFile := filepath.Join(t.TempDir(), "passwd")
+	content := "root:x:0:0:root:/root:/bin/bash\nvigo:x:1:1::/home/vigo:/bin/zsh"
+	assert.NoError(t, os.WriteFile(inputFile, []byte(content), 0o600))
+
+	output, _, err := runWith(t, []string{"XDG_CONFIG_HOME=" + configDir}, "-p", "passwd", "-nb", inputFile)
+	assert.NoError(t, err)
+
+	expectedOutput := ` root │ /bin/bash 
+ vigo │ /bin/zsh  
+`
+	assert.Equal(t, expectedOutput, output)
+}
+
+func TestTablo_Run_Read_Input_From_File_Filter_Header(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test.txt")
+	assert.NoError(t, err)
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+	content := `# comment
+SCENE       SCENER            GROUP
+C64         vigo              Bronx^zOMBiE BoYS^tRSI
+AMIGA       turbo             Bronx^zOMBiE BoYS
+AMIGA       move              Bronx^zOMBiE BoYS
+C64         street tuff       tRSI`
 
 	_, err = tmpFile.WriteString(content)
 	assert.NoError(t, err)
 	_ = tmpFile.Close()
 
-	os.Args = []string{"tablo", "-n", "-l", "\r", tmpFile.Name()}
-	resetFlags()
+	output, _, err := runWith(t, noConfig, "-n", tmpFile.Name(), "SCENE")
+	assert.NoError(t, err)
+
+	expectedOutput := `┌───────┐
+│ SCENE │
+├───────┤
+│ C64   │
+│ AMIGA │
+│ AMIGA │
+│ C64   │
+└───────┘
+`
+	assert.Equal(t, expectedOutput, output)
+}
 
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+func TestTablo_Run_Read_Input_From_File_TAB_as_LineDelimiter(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test.txt")
+	assert.NoError(t, err)
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+	content := "hello\tworld"
 
-	err = tablo.Run()
+	_, err = tmpFile.WriteString(content)
 	assert.NoError(t, err)
-	_ = w.Close()
-	os.Stdout = oldStdout
+	_ = tmpFile.Close()
 
-	output := new(BytesWriteCloser)
-	_, _ = output.ReadFrom(r)
+	output, _, err := runWith(t, noConfig, "-n", "-l", "\t", tmpFile.Name())
+	assert.NoError(t, err)
 
 	expectedOutput := `┌───────┐
 │ hello │
 │ world │
 └───────┘
 `
-	assert.Equal(t, expectedOutput, output.String())
+	assert.Equal(t, expectedOutput, output)
 }
 
-func TestTablo_Run_Read_Input_From_Non_Existing_File(t *testing.T) {
-	os.Args = []string{"tablo", "f4|<3"}
-	resetFlags()
+func TestTablo_Run_Read_Input_From_File_R_as_LineDelimiter(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test.txt")
+	assert.NoError(t, err)
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+	content := "hello\rworld"
+
+	_, err = tmpFile.WriteString(content)
+	assert.NoError(t, err)
+	_ = tmpFile.Close()
+
+	output, _, err := runWith(t, noConfig, "-n", "-l", "\r", tmpFile.Name())
+	assert.NoError(t, err)
 
-	oldStderr := os.Stderr
-	_, w, _ := os.Pipe()
-	os.Stderr = w
+	expectedOutput := `┌───────┐
+│ hello │
+│ world │
+└───────┘
+`
+	assert.Equal(t, expectedOutput, output)
+}
 
-	err := tablo.Run()
+func TestTablo_Run_Read_Input_From_Non_Existing_File(t *testing.T) {
+	_, _, err := runWith(t, noConfig, "f4|<3")
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, fs.ErrNotExist))
-	_ = w.Close()
-	os.Stderr = oldStderr
 }
 
 func TestRun_ReadFromFile_SaveToFile(t *testing.T) {
@@ -1068,10 +2360,7 @@ func TestRun_ReadFromFile_SaveToFile(t *testing.T) {
 	assert.NoError(t, err)
 	defer func() { _ = os.Remove(outputFile.Name()) }()
 
-	os.Args = []string{"tablo", "-o", outputFile.Name(), tmpFile.Name()}
-	resetFlags()
-
-	err = tablo.Run()
+	_, _, err = runWith(t, noConfig, "-o", outputFile.Name(), tmpFile.Name())
 	assert.NoError(t, err)
 
 	result, err := os.ReadFile(outputFile.Name())
@@ -1162,10 +2451,7 @@ func TestRun_ReadFromFile_SaveToJSONFile(t *testing.T) {
 	assert.NoError(t, err)
 	defer func() { _ = os.Remove(outputFile.Name()) }()
 
-	os.Args = []string{"tablo", "-j", "-f", "|", "-o", outputFile.Name(), tmpFile.Name()}
-	resetFlags()
-
-	err = tablo.Run()
+	_, _, err = runWith(t, noConfig, "-j", "-f", "|", "-o", outputFile.Name(), tmpFile.Name())
 	assert.NoError(t, err)
 
 	result, err := os.ReadFile(outputFile.Name())
@@ -1314,7 +2600,7 @@ superset-superset-worker           latest    d25cbcc60691   2 weeks ago     958M
 	assert.NoError(t, err)
 
 	expectedOutput := `┌───────────────────────────────┬────────┬──────────────┬─────────────┬───────┐
-│ REPOSITORY                    │ TAG    │ IMAGE ID     │ CREATED     │ SIZE  │
+│ REPOSITORY                    │ TAG    │ IMAGE ID     │ CREATED     │  SIZE │
 ├───────────────────────────────┼────────┼──────────────┼─────────────┼───────┤
 │ superset-superset-worker-beat │ latest │ 3292fc2e6758 │ 2 weeks ago │ 958MB │
 ├───────────────────────────────┼────────┼──────────────┼─────────────┼───────┤
@@ -1333,26 +2619,14 @@ func TestTablo_Run_FieldDelimiter_Space_ExactSplit_FromCLI(t *testing.T) {
 	assert.NoError(t, err)
 	_ = tmpFile.Close()
 
-	os.Args = []string{"tablo", "-n", "-f", " ", tmpFile.Name()}
-	resetFlags()
-
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	err = tablo.Run()
+	output, _, err := runWith(t, noConfig, "-n", "-f", " ", tmpFile.Name())
 	assert.NoError(t, err)
-	_ = w.Close()
-	os.Stdout = oldStdout
-
-	output := new(BytesWriteCloser)
-	_, _ = output.ReadFrom(r)
 
 	expectedOutput := `┌─────┬─────┐
 │ foo │ bar │
 └─────┴─────┘
 `
-	assert.Equal(t, expectedOutput, output.String())
+	assert.Equal(t, expectedOutput, output)
 }
 
 func TestTablo_Run_FieldDelimiter_Colon_PreservesEmptyFields_FromCLI(t *testing.T) {
@@ -1364,38 +2638,23 @@ func TestTablo_Run_FieldDelimiter_Colon_PreservesEmptyFields_FromCLI(t *testing.
 	assert.NoError(t, err)
 	_ = tmpFile.Close()
 
-	os.Args = []string{"tablo", "-n", "-f", ":", tmpFile.Name()}
-	resetFlags()
-
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	err = tablo.Run()
+	output, _, err := runWith(t, noConfig, "-n", "-f", ":", tmpFile.Name())
 	assert.NoError(t, err)
-	_ = w.Close()
-	os.Stdout = oldStdout
-
-	output := new(BytesWriteCloser)
-	_, _ = output.ReadFrom(r)
 
 	expectedOutput := `┌───┬───┬──┬───┐
 │ a │ b │  │ c │
 └───┴───┴──┴───┘
 `
-	assert.Equal(t, expectedOutput, output.String())
+	assert.Equal(t, expectedOutput, output)
 }
 
 func TestRun_ShowUsage_WithHelpFlag(t *testing.T) {
-	os.Args = []string{"tablo", "-h"}
-	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	var stdout, buf bytes.Buffer
 
-	var buf bytes.Buffer
-	flag.CommandLine.SetOutput(&buf)
-
-	err := tablo.Run()
+	err := tablo.RunWith(context.Background(), []string{"tablo", "-h"}, strings.NewReader(""), &stdout, &buf, nil)
 
 	assert.ErrorIs(t, err, flag.ErrHelp)
+	assert.Empty(t, stdout.String())
 	assert.Contains(t, buf.String(), "usage:")
 	assert.Contains(t, buf.String(), "tablo")
 	assert.Contains(t, buf.String(), "-bash-completion")
@@ -1403,87 +2662,61 @@ func TestRun_ShowUsage_WithHelpFlag(t *testing.T) {
 }
 
 func TestRun_PrintBashCompletion(t *testing.T) {
-	os.Args = []string{"tablo", "--bash-completion"}
-	resetFlags()
-
-	oldStdout := os.Stdout
-	r, w, err := os.Pipe()
+	output, _, err := runWith(t, noConfig, "--bash-completion")
 	assert.NoError(t, err)
-	os.Stdout = w
-	defer func() { os.Stdout = oldStdout }()
 
-	err = tablo.Run()
-	assert.NoError(t, err)
-	_ = w.Close()
-
-	output := new(BytesWriteCloser)
-	_, _ = output.ReadFrom(r)
-
-	assert.Contains(t, output.String(), "complete -F _tablo_completion -- 'tablo'")
-	assert.Contains(t, output.String(), "--complete")
+	assert.Contains(t, output, "complete -F _tablo_completion -- 'tablo'")
+	assert.Contains(t, output, "--complete")
 }
 
 func TestRun_PrintBashCompletion_AfterOtherFlags(t *testing.T) {
-	os.Args = []string{"tablo", "-n", "--bash-completion"}
-	resetFlags()
-
-	oldStdout := os.Stdout
-	r, w, err := os.Pipe()
-	assert.NoError(t, err)
-	os.Stdout = w
-	defer func() { os.Stdout = oldStdout }()
-
-	err = tablo.Run()
+	output, _, err := runWith(t, noConfig, "-n", "--bash-completion")
 	assert.NoError(t, err)
-	_ = w.Close()
-
-	output := new(BytesWriteCloser)
-	_, _ = output.ReadFrom(r)
 
-	assert.Contains(t, output.String(), "complete -F _tablo_completion -- 'tablo'")
+	assert.Contains(t, output, "complete -F _tablo_completion -- 'tablo'")
 }
 
 func TestRun_PrintBashCompletion_ShortLongFlag(t *testing.T) {
-	os.Args = []string{"tablo", "-bash-completion"}
-	resetFlags()
-
-	oldStdout := os.Stdout
-	r, w, err := os.Pipe()
+	output, _, err := runWith(t, noConfig, "-bash-completion")
 	assert.NoError(t, err)
-	os.Stdout = w
-	defer func() { os.Stdout = oldStdout }()
 
-	err = tablo.Run()
-	assert.NoError(t, err)
-	_ = w.Close()
+	assert.Contains(t, output, "complete -F _tablo_completion -- 'tablo'")
+}
 
-	output := new(BytesWriteCloser)
-	_, _ = output.ReadFrom(r)
+func TestRunWith_PrintShellCompletion(t *testing.T) {
+	tests := []struct {
+		flag string
+		want string
+	}{
+		{"--zsh-completion", "compdef _tablo 'tablo'"},
+		{"-zsh-completion", "#compdef tablo"},
+		{"--fish-completion", "complete -c 'tablo' -f -a '(__tablo_complete)'"},
+		{"-fish-completion", "function __tablo_complete"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			err := tablo.RunWith(
+				context.Background(), []string{"/usr/bin/tablo", "-n", tt.flag}, strings.NewReader(""), &stdout, &stderr, nil,
+			)
 
-	assert.Contains(t, output.String(), "complete -F _tablo_completion -- 'tablo'")
+			assert.NoError(t, err)
+			assert.Contains(t, stdout.String(), tt.want)
+			assert.Empty(t, stderr.String())
+		})
+	}
 }
 
 func TestRun_DoesNotTreatOutputValueAsBashCompletionFlag(t *testing.T) {
 	tmpDir := t.TempDir()
 	outputFile := filepath.Join(tmpDir, "--bash-completion")
 
-	os.Args = []string{"tablo", "-o", outputFile, "-version"}
-	resetFlags()
-
-	oldStdout := os.Stdout
-	r, w, err := os.Pipe()
-	assert.NoError(t, err)
-	os.Stdout = w
-	defer func() { os.Stdout = oldStdout }()
-
-	err = tablo.Run()
+	output, _, err := runWith(t, noConfig, "-o", outputFile, "-version")
 	assert.NoError(t, err)
-	_ = w.Close()
 
-	output := new(BytesWriteCloser)
-	_, _ = output.ReadFrom(r)
-
-	assert.NotContains(t, output.String(), "complete -F _tablo_completion")
+	assert.NotContains(t, output, "complete -F _tablo_completion")
 	_, statErr := os.Stat(outputFile)
 	assert.NoError(t, statErr)
 }
@@ -1494,21 +2727,106 @@ func TestRun_DoesNotTreatCompletionFlagAfterPositionalAsCommand(t *testing.T) {
 	err := os.WriteFile(inputFile, []byte("hello\n"), 0o600)
 	assert.NoError(t, err)
 
-	os.Args = []string{"tablo", inputFile, "--bash-completion"}
-	resetFlags()
+	output, _, err := runWith(t, noConfig, inputFile, "--bash-completion")
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+
+	assert.NotContains(t, output, "complete -F _tablo_completion")
+}
+
+func TestRunWith(t *testing.T) {
+	input := "name,age\nvigo,42\nturbo,7\n"
+
+	tests := []struct {
+		name   string
+		args   []string
+		env    []string
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "columns",
+			args:   []string{"-fmt", "csv", "age"},
+			stdout: "age\n42\n7\n",
+		},
+		{
+			name: "json",
+			args: []string{"-j", "-s", "age"},
+			stdout: `[
+  {
+    "name": "turbo",
+    "age": "7"
+  },
+  {
+    "name": "vigo",
+    "age": "42"
+  }
+]
+`,
+		},
+		{
+			name:   "environment",
+			args:   []string{"name"},
+			env:    []string{"TABLO_FORMAT=tsv", "HOME=/nonexistent"},
+			stdout: "name\nvigo\nturbo\n",
+		},
+		{
+			name:   "version",
+			args:   []string{"-version"},
+			stderr: tablo.Version + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+			env := append([]string{"TABLO_CONFIG=/nonexistent"}, tt.env...)
+			args := append([]string{"tablo"}, tt.args...)
+
+			err := tablo.RunWith(context.Background(), args, strings.NewReader(input), &stdout, &stderr, env)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.stdout, stdout.String())
+			assert.Equal(t, tt.stderr, stderr.String())
+		})
+	}
+}
+
+func TestRunWith_ConfigColumnsWithFileArgument(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "users.csv")
+	configFile := filepath.Join(tmpDir, "config")
+	emptyFile := filepath.Join(tmpDir, "empty")
+	assert.NoError(t, os.WriteFile(inputFile, []byte("name,age,city\nvigo,42,istanbul\n"), 0o600))
+	assert.NoError(t, os.WriteFile(configFile, []byte("[profiles.users]\ncolumns = \"name,city\"\n"), 0o600))
+	assert.NoError(t, os.WriteFile(emptyFile, nil, 0o600))
 
-	oldStdout := os.Stdout
-	r, w, err := os.Pipe()
+	stdin, err := os.Open(emptyFile)
 	assert.NoError(t, err)
-	os.Stdout = w
-	defer func() { os.Stdout = oldStdout }()
+	defer func() { _ = stdin.Close() }()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"tablo", "-p", "users", "-fmt", "csv", inputFile}
+	env := []string{"TABLO_CONFIG=" + configFile}
+
+	err = tablo.RunWith(context.Background(), args, stdin, &stdout, &stderr, env)
 
-	err = tablo.Run()
 	assert.NoError(t, err)
-	_ = w.Close()
+	assert.Equal(t, "name,city\nvigo,istanbul\n", stdout.String())
+}
 
-	output := new(BytesWriteCloser)
-	_, _ = output.ReadFrom(r)
+func TestRunWith_Errors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := tablo.RunWith(ctx, []string{"tablo"}, strings.NewReader("a,b\n"), &stdout, &stderr, nil)
+	assert.ErrorIs(t, err, context.Canceled)
 
-	assert.NotContains(t, output.String(), "complete -F _tablo_completion")
+	err = tablo.RunWith(context.Background(), []string{"tablo", "-nope"}, strings.NewReader(""), &stdout, &stderr, nil)
+	assert.ErrorIs(t, err, tablo.ErrInvalidFlag)
+	assert.ErrorContains(t, err, "flag provided but not defined: -nope")
+	assert.Contains(t, stderr.String(), "flag provided but not defined: -nope")
+	assert.Contains(t, stderr.String(), "usage:")
 }
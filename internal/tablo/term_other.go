@@ -0,0 +1,13 @@
+//go:build !unix
+
+package tablo
+
+import "os"
+
+func terminalColumns(*os.File) int {
+	return 0
+}
+
+func terminalRows(*os.File) int {
+	return 0
+}
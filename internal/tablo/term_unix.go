@@ -0,0 +1,27 @@
+//go:build unix
+
+package tablo
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func terminalColumns(f *os.File) int {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+
+	return int(ws.Col)
+}
+
+func terminalRows(f *os.File) int {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+
+	return int(ws.Row)
+}
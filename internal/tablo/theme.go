@@ -0,0 +1,72 @@
+package tablo
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+var builtinThemes = map[string]table.ColorOptions{
+	"dark": {
+		Header:       text.Colors{text.Bold, text.FgHiCyan},
+		Footer:       text.Colors{text.Bold, text.FgHiCyan},
+		RowAlternate: text.Colors{text.BgHiBlack},
+	},
+	"light": {
+		Header:       text.Colors{text.Bold, text.FgBlue},
+		Footer:       text.Colors{text.Bold, text.FgBlue},
+		RowAlternate: text.Colors{text.BgWhite},
+	},
+}
+
+// themeNames returns the built-in theme names.
+func themeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// resolveTheme returns the header, row and footer colors of a built-in
+// theme.
+func resolveTheme(name string) (table.ColorOptions, error) {
+	theme, ok := builtinThemes[strings.ToLower(name)]
+	if !ok {
+		return table.ColorOptions{}, fmt.Errorf(
+			"%w, unknown theme %q, use one of %s", ErrInvalidValue, name, strings.Join(themeNames(), ", "),
+		)
+	}
+
+	return theme, nil
+}
+
+func styleColorFields(colors *table.ColorOptions) map[string]*text.Colors {
+	return map[string]*text.Colors{
+		"border":        &colors.Border,
+		"footer":        &colors.Footer,
+		"header":        &colors.Header,
+		"row":           &colors.Row,
+		"row_alternate": &colors.RowAlternate,
+		"separator":     &colors.Separator,
+	}
+}
+
+// rowColors returns the colors of the n-th data row, 1-based; even rows use
+// the alternate colors when the style has them.
+func rowColors(colors table.ColorOptions, n int) text.Colors {
+	if n%2 == 0 && colors.RowAlternate != nil {
+		return colors.RowAlternate
+	}
+	if colors.Row == nil {
+		// not nil, go-pretty would fall back to its own striping.
+		return text.Colors{}
+	}
+
+	return colors.Row
+}
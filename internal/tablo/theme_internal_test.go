@@ -0,0 +1,50 @@
+package tablo
+
+import (
+	"testing"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThemeNames(t *testing.T) {
+	assert.Equal(t, []string{"dark", "light"}, themeNames())
+}
+
+func TestResolveTheme(t *testing.T) {
+	theme, err := resolveTheme("Dark")
+
+	require.NoError(t, err)
+	assert.Equal(t, text.Colors{text.Bold, text.FgHiCyan}, theme.Header)
+	assert.Equal(t, text.Colors{text.BgHiBlack}, theme.RowAlternate)
+
+	_, err = resolveTheme("neon")
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.ErrorContains(t, err, "dark, light")
+}
+
+func TestRowColors(t *testing.T) {
+	colors := table.ColorOptions{Row: text.Colors{text.FgWhite}, RowAlternate: text.Colors{text.BgBlack}}
+
+	assert.Equal(t, text.Colors{text.FgWhite}, rowColors(colors, 1))
+	assert.Equal(t, text.Colors{text.BgBlack}, rowColors(colors, 2))
+	assert.Equal(t, text.Colors{}, rowColors(table.ColorOptions{}, 1))
+}
+
+func TestTablo_TableStyle_Theme(t *testing.T) {
+	theme := builtinThemes["dark"]
+	tbl := &Tablo{Theme: &theme, ColorMode: ColorAlways}
+
+	style := tbl.tableStyle()
+
+	assert.Equal(t, theme, style.Color)
+
+	tbl.ColorMode = ColorNever
+	assert.Equal(t, table.ColorOptionsDefault, tbl.tableStyle().Color)
+
+	tbl.ColorMode = ColorAlways
+	tbl.Format = FormatCSV
+	assert.Equal(t, table.ColorOptionsDefault, tbl.tableStyle().Color)
+}
@@ -0,0 +1,283 @@
+package tablo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONTypes defines how cell values are typed in JSON output.
+type JSONTypes string
+
+// json typing modes.
+const (
+	JSONTypesStrings JSONTypes = "strings"
+	JSONTypesInfer   JSONTypes = "infer"
+	JSONTypesSchema  JSONTypes = "schema"
+)
+
+// jsonType is the JSON type of a column.
+type jsonType string
+
+// column types.
+const (
+	jsonTypeString jsonType = "string"
+	jsonTypeInt    jsonType = "int"
+	jsonTypeFloat  jsonType = "float"
+	jsonTypeBool   jsonType = "bool"
+	jsonTypeDate   jsonType = "date"
+	jsonTypeAuto   jsonType = "auto"
+
+	// jsonTypeNullString is an inferred string column, unlike an explicit
+	// string column its empty cells are null.
+	jsonTypeNullString jsonType = "null-string"
+)
+
+const jsonSchemaSeparator = ":"
+
+var (
+	jsonTypesModes = []JSONTypes{JSONTypesStrings, JSONTypesInfer, JSONTypesSchema}
+	jsonTypeNames  = map[string]jsonType{
+		"string":  jsonTypeString,
+		"str":     jsonTypeString,
+		"int":     jsonTypeInt,
+		"integer": jsonTypeInt,
+		"float":   jsonTypeFloat,
+		"number":  jsonTypeFloat,
+		"bool":    jsonTypeBool,
+		"boolean": jsonTypeBool,
+		"date":    jsonTypeDate,
+		"auto":    jsonTypeAuto,
+	}
+	jsonIntPattern    = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+	jsonDateLayouts   = []string{time.DateOnly, time.DateTime, "2006-01-02T15:04:05", time.RFC3339Nano}
+)
+
+// jsonSchemaField sets the type of a column given by header name or, when
+// index is not negative, by zero-based output column index.
+type jsonSchemaField struct {
+	column   string
+	index    int
+	dataType jsonType
+}
+
+func parseJSONTypes(s string) (JSONTypes, error) {
+	mode := JSONTypes(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(jsonTypesModes, mode) {
+		return "", fmt.Errorf("%w, unknown json types mode %q", ErrInvalidValue, s)
+	}
+
+	return mode, nil
+}
+
+func jsonTypesNames() []string {
+	names := make([]string, 0, len(jsonTypesModes))
+	for _, mode := range jsonTypesModes {
+		names = append(names, string(mode))
+	}
+
+	return names
+}
+
+// parseJSONSchema parses a comma separated list of COLUMN:TYPE entries
+// where COLUMN is a header name or a 1-based column index.
+func parseJSONSchema(spec string) ([]jsonSchemaField, error) {
+	var fields []jsonSchemaField
+
+	for item := range strings.SplitSeq(spec, ",") {
+		item = strings.TrimSpace(item)
+		idx := strings.LastIndex(item, jsonSchemaSeparator)
+		if idx <= 0 {
+			return nil, fmt.Errorf("%w, json schema entry %q must be COLUMN:TYPE", ErrInvalidValue, item)
+		}
+
+		column, typeName := item[:idx], item[idx+1:]
+		dataType, ok := jsonTypeNames[strings.ToLower(typeName)]
+		if !ok {
+			return nil, fmt.Errorf("%w, json schema entry %q has unknown type %q", ErrInvalidValue, item, typeName)
+		}
+
+		field := jsonSchemaField{column: column, index: -1, dataType: dataType}
+		if n, err := strconv.Atoi(column); err == nil {
+			if n < 1 {
+				return nil, fmt.Errorf("%w, json schema column index %d must be greater than zero", ErrInvalidValue, n)
+			}
+			field.index = n - 1
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func isJSONBool(value string) bool {
+	return strings.EqualFold(value, "true") || strings.EqualFold(value, "false")
+}
+
+func isJSONDate(value string) bool {
+	for _, layout := range jsonDateLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inferColumnType picks the narrowest type that fits every non-empty value
+// of a column. Numbers with leading zeros stay strings and so do dates.
+func inferColumnType(rows [][]string, column int) jsonType {
+	dataType := jsonType("")
+
+	for _, row := range rows {
+		value := fieldAt(row, column)
+		if value == "" {
+			continue
+		}
+
+		var valueType jsonType
+		switch {
+		case jsonIntPattern.MatchString(value):
+			valueType = jsonTypeInt
+		case jsonNumberPattern.MatchString(value):
+			valueType = jsonTypeFloat
+		case isJSONBool(value):
+			valueType = jsonTypeBool
+		default:
+			return jsonTypeNullString
+		}
+
+		switch {
+		case dataType == "" || dataType == valueType:
+			dataType = valueType
+		case (dataType == jsonTypeInt || dataType == jsonTypeFloat) &&
+			(valueType == jsonTypeInt || valueType == jsonTypeFloat):
+			dataType = jsonTypeFloat
+		default:
+			return jsonTypeNullString
+		}
+	}
+
+	if dataType == "" {
+		return jsonTypeNullString
+	}
+
+	return dataType
+}
+
+// columnTypes returns the JSON type of every output column, nil when all
+// values are written as strings.
+func (t *Tablo) columnTypes(dataset jsonDataset) ([]jsonType, error) {
+	if t.JSONTypes == "" || t.JSONTypes == JSONTypesStrings {
+		return nil, nil
+	}
+
+	columns := len(dataset.headers)
+	for _, row := range dataset.rows {
+		columns = max(columns, len(row))
+	}
+
+	types := make([]jsonType, columns)
+	for i := range types {
+		types[i] = jsonTypeAuto
+		if t.JSONTypes == JSONTypesSchema {
+			types[i] = jsonTypeString
+		}
+	}
+
+	if t.JSONTypes == JSONTypesSchema {
+		if len(t.JSONSchema) == 0 {
+			return nil, fmt.Errorf("%w, json types schema requires a json schema", ErrInvalidValue)
+		}
+		for _, field := range t.JSONSchema {
+			idx := field.index
+			if idx < 0 {
+				idx = slices.IndexFunc(dataset.headers, func(header string) bool {
+					return strings.EqualFold(header, field.column)
+				})
+			}
+			if idx < 0 || idx >= columns {
+				return nil, fmt.Errorf("%w, json schema column %q not found", ErrInvalidValue, field.column)
+			}
+			types[idx] = field.dataType
+		}
+	}
+
+	for i, dataType := range types {
+		if dataType == jsonTypeAuto {
+			types[i] = inferColumnType(dataset.rows, i)
+		}
+	}
+
+	return types, nil
+}
+
+// typedJSONValue encodes a cell with the column type. Empty cells are null
+// unless the schema declares the column as string.
+func typedJSONValue(value string, dataType jsonType) (json.RawMessage, error) {
+	if value == "" && dataType != jsonTypeString {
+		return json.RawMessage("null"), nil
+	}
+
+	switch dataType {
+	case jsonTypeInt:
+		if !jsonIntPattern.MatchString(value) {
+			return nil, fmt.Errorf("%q is not an int", value)
+		}
+		return json.RawMessage(value), nil
+	case jsonTypeFloat:
+		if !jsonNumberPattern.MatchString(value) {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return json.RawMessage(value), nil
+	case jsonTypeBool:
+		if !isJSONBool(value) {
+			return nil, fmt.Errorf("%q is not a bool", value)
+		}
+		return json.RawMessage(strings.ToLower(value)), nil
+	case jsonTypeDate:
+		if !isJSONDate(value) {
+			return nil, fmt.Errorf("%q is not an ISO 8601 date", value)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSONString(&buf, value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// typedRow encodes a row with the column types, errors carry the 1-based
+// data row number and the column name.
+func typedRow(dataset jsonDataset, rowIndex int, types []jsonType) ([]json.RawMessage, error) {
+	row := dataset.rows[rowIndex]
+
+	columns := len(row)
+	if dataset.hasHeader {
+		columns = len(dataset.headers)
+	}
+
+	values := make([]json.RawMessage, columns)
+	for i := range values {
+		value, err := typedJSONValue(fieldAt(row, i), types[i])
+		if err != nil {
+			column := strconv.Itoa(i + 1)
+			if dataset.hasHeader {
+				column = dataset.headers[i]
+			}
+
+			return nil, fmt.Errorf("%w, row %d, column %q: %w", ErrInvalidValue, rowIndex+1, column, err)
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,196 @@
+package tablo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONTypes(t *testing.T) {
+	mode, err := parseJSONTypes(" Infer ")
+
+	require.NoError(t, err)
+	assert.Equal(t, JSONTypesInfer, mode)
+
+	_, err = parseJSONTypes("numbers")
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestParseJSONSchema(t *testing.T) {
+	schema, err := parseJSONSchema("SIZE:int, created at:date,3:Boolean")
+
+	require.NoError(t, err)
+	assert.Equal(t, []jsonSchemaField{
+		{column: "SIZE", index: -1, dataType: jsonTypeInt},
+		{column: "created at", index: -1, dataType: jsonTypeDate},
+		{column: "3", index: 2, dataType: jsonTypeBool},
+	}, schema)
+
+	for _, spec := range []string{"SIZE", ":int", "SIZE:bytes", "0:int", "SIZE:int,"} {
+		_, err = parseJSONSchema(spec)
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestInferColumnType(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   jsonType
+	}{
+		{[]string{"1", "-20", ""}, jsonTypeInt},
+		{[]string{"1", "2.5", "1e3"}, jsonTypeFloat},
+		{[]string{"true", "False"}, jsonTypeBool},
+		{[]string{"007", "8"}, jsonTypeNullString},
+		{[]string{"1", "true"}, jsonTypeNullString},
+		{[]string{"2024-01-02"}, jsonTypeNullString},
+		{[]string{"", ""}, jsonTypeNullString},
+		{[]string{"NaN", "Inf"}, jsonTypeNullString},
+	}
+
+	for _, tt := range tests {
+		rows := make([][]string, len(tt.values))
+		for i, value := range tt.values {
+			rows[i] = []string{value}
+		}
+
+		assert.Equal(t, tt.want, inferColumnType(rows, 0), tt.values)
+	}
+}
+
+func TestTypedJSONValue(t *testing.T) {
+	tests := []struct {
+		value    string
+		dataType jsonType
+		want     string
+	}{
+		{"42", jsonTypeInt, "42"},
+		{"4.2", jsonTypeFloat, "4.2"},
+		{"TRUE", jsonTypeBool, "true"},
+		{"", jsonTypeInt, "null"},
+		{"", jsonTypeNullString, "null"},
+		{"", jsonTypeString, `""`},
+		{"2024-01-02T03:04:05Z", jsonTypeDate, `"2024-01-02T03:04:05Z"`},
+		{`a "b"`, jsonTypeNullString, `"a \"b\""`},
+	}
+
+	for _, tt := range tests {
+		got, err := typedJSONValue(tt.value, tt.dataType)
+
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, string(got), tt.value)
+	}
+
+	for value, dataType := range map[string]jsonType{
+		"4.2":   jsonTypeInt,
+		"1,5":   jsonTypeFloat,
+		"yes":   jsonTypeBool,
+		"01/02": jsonTypeDate,
+	} {
+		_, err := typedJSONValue(value, dataType)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestTablo_RenderJSON_Infer(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		FieldDelimiter: ',',
+		JSONTypes:      JSONTypesInfer,
+	}
+
+	err := tbl.renderJSON(tbl.buildJSONDataset(tbl.splitRecords([]string{"name,size,ok,zip", "vigo,10,true,01234", "john,2.5,,"})))
+
+	require.NoError(t, err)
+	assert.Equal(t, `[
+  {
+    "name": "vigo",
+    "size": 10,
+    "ok": true,
+    "zip": "01234"
+  },
+  {
+    "name": "john",
+    "size": 2.5,
+    "ok": null,
+    "zip": null
+  }
+]
+`, out.String())
+}
+
+func TestTablo_RenderJSON_Infer_WithoutHeader(t *testing.T) {
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		FieldDelimiter: ',',
+		JSONTypes:      JSONTypesInfer,
+	}
+
+	err := tbl.renderJSON(tbl.buildJSONDataset(tbl.splitRecords([]string{"1,x", "2,"})))
+
+	require.NoError(t, err)
+	assert.Equal(t, "[\n  [\n    1,\n    \"x\"\n  ],\n  [\n    2,\n    null\n  ]\n]\n", out.String())
+}
+
+func TestTablo_RenderJSON_Schema(t *testing.T) {
+	schema, err := parseJSONSchema("id:int,score:auto")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	tbl := &Tablo{
+		Output:         nopWriteCloser{&out},
+		FieldDelimiter: ',',
+		JSONTypes:      JSONTypesSchema,
+		JSONSchema:     schema,
+	}
+
+	err = tbl.renderJSON(tbl.buildJSONDataset(tbl.splitRecords([]string{"id,score,zip", "1,2.5,01234", "2,,"})))
+
+	require.NoError(t, err)
+	assert.Equal(t, `[
+  {
+    "id": 1,
+    "score": 2.5,
+    "zip": "01234"
+  },
+  {
+    "id": 2,
+    "score": null,
+    "zip": ""
+  }
+]
+`, out.String())
+}
+
+func TestTablo_RenderJSON_Schema_Errors(t *testing.T) {
+	tests := map[string]string{
+		"":          "requires a json schema",
+		"age:int":   `json schema column "age" not found`,
+		"9:int":     `json schema column "9" not found`,
+		"score:int": `row 2, column "score": "2.5" is not an int`,
+	}
+
+	for spec, want := range tests {
+		schema, err := parseJSONSchema(spec)
+		if spec == "" {
+			schema, err = nil, nil
+		}
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		tbl := &Tablo{
+			Output:         nopWriteCloser{&out},
+			FieldDelimiter: ',',
+			JSONTypes:      JSONTypesSchema,
+			JSONSchema:     schema,
+		}
+
+		err = tbl.renderJSON(tbl.buildJSONDataset(tbl.splitRecords([]string{"id,score", "1,2", "2,2.5"})))
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+		assert.ErrorContains(t, err, want, spec)
+		assert.Empty(t, out.String(), spec)
+	}
+}
@@ -3,7 +3,6 @@ package tablo
 import (
 	"flag"
 	"fmt"
-	"os"
 )
 
 const usage = `usage: %[1]s [-flags] [COLUMN] [COLUMN] [COLUMN]
@@ -13,8 +12,13 @@ const usage = `usage: %[1]s [-flags] [COLUMN] [COLUMN] [COLUMN]
   -version                          display version information (%s)
   -bash-completion, --bash-completion
                                     print bash completion script
+  -zsh-completion, --zsh-completion
+                                    print zsh completion script
+  -fish-completion, --fish-completion
+                                    print fish completion script
   -f, -field-delimiter-char         %s
                                     (omit for smart split: 2+ whitespace)
+  -f-regex, -field-delimiter-regex  %s
   -l, -line-delimiter-char          %s
                                     (default: "\n")
   -n, -no-separate-rows             %s
@@ -22,8 +26,42 @@ const usage = `usage: %[1]s [-flags] [COLUMN] [COLUMN] [COLUMN]
   -nh, -no-headers                  %s
   -fi, -filter-indexes              %s
   -j, -json                         %s
+  -jt, -json-types                  %s
+                                    (default: strings)
+  -js, -json-schema                 %s
+  -fmt, -format                     %s
+                                    (default: table)
+  -a, -align                        %s
+  -mw, -max-width                   %s
+                                    (default: terminal width, 0 disables)
+  -wm, -width-mode                  %s
+                                    (default: wrap)
+  -ft, -footer                      %s
+  -gb, -group-by                    %s
+  -ag, -agg                         %s
+  -rn, -rename                      %s
+  -ac, -add-column                  %s
+  -sy, -style                       %s
+                                    (default: light)
+  -th, -theme                       %s
+                                    (NO_COLOR and -c never disable it)
+  -if, -input-format                %s
+                                    (default: auto, detects json and jsonl)
+  -fw, -widths                      %s
+  -c, -color                        %s
+                                    (default: auto, NO_COLOR disables)
+  -rs, -raw-split                   %s
+                                    (default: RFC 4180 quoted fields)
+  -s, -sort                         %s
+  -w, -where                        %s
+  -st, -stream                      %s
+  -sw, -stream-window               %s
+                                    (default: 20)
+  -vw, -view                        %s
   -o, -output                       %s
                                     (default "stdout")
+  -p, -profile                      %s
+  -sc, -show-config                 %s
 
   examples:
 
@@ -37,12 +75,37 @@ const usage = `usage: %[1]s [-flags] [COLUMN] [COLUMN] [COLUMN]
   $ cat /etc/passwd | %[1]s -f ":"
   $ cat /etc/passwd | %[1]s -f ":" -n
   $ cat /etc/passwd | %[1]s -n -f ":" -fi "1,5"   # show columns 1 and 5 only
+  $ cat /etc/passwd | %[1]s -n -f ":" -fi "5-"    # show columns 5 to the last
+  $ docker ps | %[1]s '!PORTS'                    # all columns except PORTS
   $ cat /etc/passwd | %[1]s -n -f ":" -nb nobody  # list users only (macos)
   $ cat /etc/passwd | %[1]s -n -f ":" -nb root    # list users only (linux)
   $ docker images | %[1]s
   $ docker images | %[1]s REPOSITORY              # show only REPOSITORY colum
   $ docker images | %[1]s REPOSITORY "IMAGE ID"   # show REPOSITORY and IMAGE ID colums
   $ docker images | %[1]s -j                       # render rows as json
+  $ cat /etc/passwd | %[1]s -f ":" -j -jt infer   # numbers and bools as json values
+  $ cat data.csv | %[1]s -j -js "id:int,price:float,active:bool"
+  $ docker ps | %[1]s -fmt markdown                # paste into pull requests
+  $ cat /path/to/file.csv | %[1]s -fmt html > table.html
+  $ docker ps | %[1]s -sy rounded
+  $ docker images | %[1]s -a "REPOSITORY:right,SIZE:left"
+  $ docker ps | %[1]s -mw "100,COMMAND:30" -wm ellipsis
+  $ docker images | %[1]s -ft "SIZE:sum,REPOSITORY:count"
+  $ docker images | %[1]s -gb REPOSITORY -ag "SIZE:sum,TAG:count"
+  $ kubectl get pods -o json | %[1]s metadata.name status.phase
+  $ cat deployment.yaml | %[1]s -if yaml
+  $ docker ps | %[1]s -sy ~/.config/tablo/style.toml
+  $ docker images | %[1]s -s "SIZE:desc,REPOSITORY"
+  $ cat /etc/passwd | %[1]s -f ":" -s "3:numeric"   # sort by uid
+  $ docker images | %[1]s -w "SIZE > 100mb and CREATED < 2w"
+  $ docker ps | %[1]s -w 'STATUS ~ "^Up" and not NAMES contains test'
+  $ kubectl get pods -w | %[1]s -st              # render rows as they arrive
+  $ tail -f access.log | %[1]s -f " " -st -j     # stream json lines
+  $ docker images | %[1]s -vw                    # browse in a pager
+
+  # config file profiles (~/.config/tablo/config)
+  $ cat /etc/passwd | %[1]s -p passwd
+  $ %[1]s -p passwd -sc                          # show effective config
 
   # save output to a file
   $ docker images | %[1]s -o /path/to/docker-images.txt REPOSITORY "IMAGE ID"
@@ -52,6 +115,7 @@ const usage = `usage: %[1]s [-flags] [COLUMN] [COLUMN] [COLUMN]
 
   # csv files
   $ cat /path/to/file.csv | %[1]s -f ";"
+  $ cat /path/to/file.csv | %[1]s -f "," -rs      # do not parse quoted fields
   $ cat /path/to/file.csv | %[1]s -f ";" -n
   $ cat /path/to/file.csv | %[1]s -f ";" -n -nb
   $ cat /path/to/file.csv | %[1]s -f ";" -n -nb -nh
@@ -59,12 +123,18 @@ const usage = `usage: %[1]s [-flags] [COLUMN] [COLUMN] [COLUMN]
 
 `
 
-func showUsage() {
-	binaryName := os.Args[0]
+// usageFunc returns the usage function of a flag set.
+func usageFunc(flags *flag.FlagSet, binaryName string, getenv func(string) string) func() {
+	return func() {
+		showUsage(flags, binaryName, getenv)
+	}
+}
+
+func showUsage(flags *flag.FlagSet, binaryName string, getenv func(string) string) {
 	versionInformation := Version
 
-	if os.Getenv("PRINT_HELP_FOR_README") != "" {
-		binaryName = "tablo"
+	if getenv("PRINT_HELP_FOR_README") != "" {
+		binaryName = defaultBinaryName
 		versionInformation = "X.X.X"
 	}
 
@@ -72,17 +142,42 @@ func showUsage() {
 		binaryName,
 		versionInformation,
 		helpFieldDelimiterChar,
+		helpFieldDelimiterRe,
 		helpLineDelimiterChar,
 		helpNoSeparateRows,
 		helpNoBorders,
 		helpNoHeaders,
 		helpFilterIndexes,
 		helpJSONOutput,
+		helpJSONTypes,
+		helpJSONSchema,
+		helpFormat,
+		helpAlign,
+		helpMaxWidth,
+		helpWidthMode,
+		helpFooter,
+		helpGroupBy,
+		helpAggregates,
+		helpRename,
+		helpAddColumn,
+		helpStyle,
+		helpTheme,
+		helpInputFormat,
+		helpWidths,
+		helpColor,
+		helpRawSplit,
+		helpSort,
+		helpWhere,
+		helpStream,
+		helpStreamWindow,
+		helpView,
 		helpOutput,
+		helpProfile,
+		helpShowConfig,
 	}
-	fmt.Fprintf(flag.CommandLine.Output(), usage, args...)
+	fmt.Fprintf(flags.Output(), usage, args...)
 
-	if os.Getenv("PRINT_DEFAULTS") != "" {
-		flag.PrintDefaults()
+	if getenv("PRINT_DEFAULTS") != "" {
+		flags.PrintDefaults()
 	}
 }
@@ -0,0 +1,462 @@
+package tablo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+const (
+	viewMaxCellWidth   = 40
+	viewDefaultColumns = 80
+	viewDefaultRows    = 24
+	viewChromeRows     = 3 // header, separator and status line
+	viewColumnGap      = " │ "
+	viewReadBufferSize = 64
+	viewNoSort         = -1
+
+	escEnterView = "\x1b[?1049h\x1b[?25l"
+	escLeaveView = "\x1b[?25h\x1b[?1049l"
+	escHome      = "\x1b[H"
+	escClearLine = "\x1b[K"
+	escClearDown = "\x1b[J"
+	escBold      = "\x1b[1m"
+	escReverse   = "\x1b[7m"
+	escReset     = "\x1b[0m"
+)
+
+// view keys, printable keys are passed as they are.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyLeft      = "left"
+	keyRight     = "right"
+	keyPageUp    = "pgup"
+	keyPageDown  = "pgdn"
+	keyHome      = "home"
+	keyEnd       = "end"
+	keyEnter     = "enter"
+	keyEscape    = "esc"
+	keyBackspace = "backspace"
+	keyInterrupt = "ctrl-c"
+)
+
+var viewEscapeKeys = map[string]string{
+	"\x1b[A":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1b[C":  keyRight,
+	"\x1b[D":  keyLeft,
+	"\x1bOA":  keyUp,
+	"\x1bOB":  keyDown,
+	"\x1bOC":  keyRight,
+	"\x1bOD":  keyLeft,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+	"\x1b[H":  keyHome,
+	"\x1b[F":  keyEnd,
+	"\x1b[1~": keyHome,
+	"\x1b[4~": keyEnd,
+}
+
+// parseViewKeys splits raw terminal input into key names.
+func parseViewKeys(b []byte) []string {
+	var keys []string
+	s := string(b)
+
+	for s != "" {
+		if strings.HasPrefix(s, "\x1b") {
+			matched := false
+			for seq, key := range viewEscapeKeys {
+				if strings.HasPrefix(s, seq) {
+					keys, s, matched = append(keys, key), s[len(seq):], true
+					break
+				}
+			}
+			if !matched {
+				keys, s = append(keys, keyEscape), s[1:]
+			}
+			continue
+		}
+
+		r := []rune(s)[0]
+		s = s[len(string(r)):]
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, keyEnter)
+		case '\x7f', '\b':
+			keys = append(keys, keyBackspace)
+		case '\x03':
+			keys = append(keys, keyInterrupt)
+		default:
+			keys = append(keys, string(r))
+		}
+	}
+
+	return keys
+}
+
+// viewer is the -view pager state. Rows are kept as parsed, order holds the
+// display order after sorting.
+type viewer struct {
+	headers    []string
+	rows       [][]string
+	order      []int
+	widths     []int
+	hidden     []bool
+	column     int
+	left       int
+	top        int
+	match      int
+	sortColumn int
+	sortDesc   bool
+	search     string
+	searching  bool
+	width      int
+	height     int
+}
+
+func newViewer(headers []string, rows [][]string) *viewer {
+	columns := len(headers)
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	if headers == nil {
+		for i := range columns {
+			headers = append(headers, strconv.Itoa(i+1))
+		}
+	}
+
+	v := &viewer{
+		headers:    headers,
+		rows:       rows,
+		order:      make([]int, len(rows)),
+		widths:     make([]int, columns),
+		hidden:     make([]bool, columns),
+		sortColumn: viewNoSort,
+		width:      viewDefaultColumns,
+		height:     viewDefaultRows,
+	}
+	for i := range rows {
+		v.order[i] = i
+	}
+	measure := func(fields []string) {
+		for i, field := range fields {
+			v.widths[i] = min(max(v.widths[i], text.StringWidthWithoutEscSequences(streamCell(field))), viewMaxCellWidth)
+		}
+	}
+	measure(headers)
+	for _, row := range rows {
+		measure(row)
+	}
+
+	return v
+}
+
+func (v *viewer) pageSize() int {
+	return max(v.height-viewChromeRows, 1)
+}
+
+func (v *viewer) scrollTo(top int) {
+	v.top = max(min(top, len(v.order)-v.pageSize()), 0)
+}
+
+// selectColumn moves the selected column by step, skipping hidden columns,
+// and scrolls horizontally to keep it visible.
+func (v *viewer) selectColumn(step int) {
+	for i := v.column + step; i >= 0 && i < len(v.headers); i += step {
+		if !v.hidden[i] {
+			v.column = i
+			break
+		}
+	}
+	v.left = min(v.left, v.column)
+	for v.left < v.column && !v.fits(v.left, v.column) {
+		v.left++
+	}
+}
+
+// fits reports whether the columns from left to last fit the screen.
+func (v *viewer) fits(left, last int) bool {
+	width := 0
+	for i := left; i <= last; i++ {
+		if v.hidden[i] {
+			continue
+		}
+		if width > 0 {
+			width += text.StringWidth(viewColumnGap)
+		}
+		width += v.widths[i]
+	}
+
+	return width <= v.width
+}
+
+func (v *viewer) hideColumn() {
+	visible := 0
+	for _, hidden := range v.hidden {
+		if !hidden {
+			visible++
+		}
+	}
+	if visible <= 1 {
+		return
+	}
+
+	v.hidden[v.column] = true
+	current := v.column
+	if v.selectColumn(1); v.column == current {
+		v.selectColumn(-1)
+	}
+}
+
+func (v *viewer) showColumns() {
+	clear(v.hidden)
+}
+
+// sortByColumn sorts by the selected column, again on the same column
+// reverses the order.
+func (v *viewer) sortByColumn() {
+	if v.sortColumn == v.column {
+		v.sortDesc = !v.sortDesc
+	} else {
+		v.sortColumn, v.sortDesc = v.column, false
+	}
+
+	slices.SortStableFunc(v.order, func(a, b int) int {
+		c := compareSortValues(fieldAt(v.rows[a], v.sortColumn), fieldAt(v.rows[b], v.sortColumn), SortNatural)
+		if v.sortDesc {
+			return -c
+		}
+
+		return c
+	})
+	v.scrollTo(0)
+}
+
+func (v *viewer) cellMatches(value string) bool {
+	return v.search != "" && strings.Contains(strings.ToLower(value), strings.ToLower(v.search))
+}
+
+func (v *viewer) rowMatches(row []string) bool {
+	for i, value := range row {
+		if i < len(v.hidden) && !v.hidden[i] && v.cellMatches(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findMatch scrolls to the next row matching the search, starting at the
+// given position and wrapping around.
+func (v *viewer) findMatch(from, step int) {
+	n := len(v.order)
+	for i := range n {
+		pos := ((from+i*step)%n + n) % n
+		if v.rowMatches(v.rows[v.order[pos]]) {
+			v.match = pos
+			v.scrollTo(pos)
+
+			return
+		}
+	}
+}
+
+// handleSearchKey edits the search query, the view follows the first match
+// as the query is typed.
+func (v *viewer) handleSearchKey(key string) {
+	switch key {
+	case keyEnter:
+		v.searching = false
+	case keyEscape, keyInterrupt:
+		v.searching, v.search = false, ""
+	case keyBackspace:
+		if r := []rune(v.search); len(r) > 0 {
+			v.search = string(r[:len(r)-1])
+		}
+	default:
+		if len([]rune(key)) != 1 {
+			return
+		}
+		v.search += key
+		v.findMatch(v.match, 1)
+	}
+}
+
+// handleKey applies a key and reports whether the viewer should quit.
+func (v *viewer) handleKey(key string) bool {
+	if v.searching {
+		v.handleSearchKey(key)
+
+		return false
+	}
+
+	switch key {
+	case "q", keyEscape, keyInterrupt:
+		return true
+	case "j", keyDown, keyEnter:
+		v.scrollTo(v.top + 1)
+	case "k", keyUp:
+		v.scrollTo(v.top - 1)
+	case " ", "f", keyPageDown:
+		v.scrollTo(v.top + v.pageSize())
+	case "b", keyPageUp:
+		v.scrollTo(v.top - v.pageSize())
+	case "g", keyHome:
+		v.scrollTo(0)
+	case "G", keyEnd:
+		v.scrollTo(len(v.order))
+	case "l", keyRight:
+		v.selectColumn(1)
+	case "h", keyLeft:
+		v.selectColumn(-1)
+	case "x":
+		v.hideColumn()
+	case "X":
+		v.showColumns()
+	case "s":
+		v.sortByColumn()
+	case "/":
+		v.searching, v.search, v.match = true, "", v.top
+	case "n":
+		v.findMatch(v.match+1, 1)
+	case "N":
+		v.findMatch(v.match-1, -1)
+	}
+
+	return false
+}
+
+// line renders the visible columns of a row clipped to the screen width.
+func (v *viewer) line(fields []string, header bool) string {
+	var b strings.Builder
+	width := 0
+
+	for i := v.left; i < len(v.headers) && width < v.width; i++ {
+		if v.hidden[i] {
+			continue
+		}
+		if width > 0 {
+			b.WriteString(viewColumnGap)
+			width += text.StringWidth(viewColumnGap)
+		}
+
+		value := streamCell(fieldAt(fields, i))
+		cell := text.AlignLeft.Apply(text.Snip(value, v.widths[i], streamSnipIndicator), v.widths[i])
+		if room := v.width - width; text.StringWidth(cell) > room {
+			cell = text.Trim(cell, max(room, 0))
+		}
+		width += text.StringWidth(cell)
+
+		switch {
+		case header && i == v.column:
+			cell = escBold + escReverse + cell + escReset
+		case header:
+			cell = escBold + cell + escReset
+		case v.cellMatches(value):
+			cell = escReverse + cell + escReset
+		}
+		b.WriteString(cell)
+	}
+
+	return b.String()
+}
+
+func (v *viewer) status() string {
+	if v.searching {
+		return "/" + v.search
+	}
+
+	last := min(v.top+v.pageSize(), len(v.order))
+	status := fmt.Sprintf("rows %d-%d of %d", min(v.top+1, last), last, len(v.order))
+	if v.sortColumn != viewNoSort {
+		order := "asc"
+		if v.sortDesc {
+			order = "desc"
+		}
+		status += fmt.Sprintf(" | sort %s %s", v.headers[v.sortColumn], order)
+	}
+	if v.search != "" {
+		status += " | /" + v.search
+	}
+
+	return status + " | h/l column, s sort, x hide, X show, / search, q quit"
+}
+
+// frame renders the whole screen, the header row stays on top.
+func (v *viewer) frame() string {
+	var b strings.Builder
+	b.WriteString(escHome)
+
+	b.WriteString(v.line(v.headers, true) + escClearLine + "\r\n")
+	b.WriteString(strings.Repeat("─", v.width) + escClearLine + "\r\n")
+	for i := range v.pageSize() {
+		if pos := v.top + i; pos < len(v.order) {
+			b.WriteString(v.line(v.rows[v.order[pos]], false))
+		}
+		b.WriteString(escClearLine + "\r\n")
+	}
+	b.WriteString(escReverse + text.Trim(v.status(), v.width) + escReset + escClearLine + escClearDown)
+
+	return b.String()
+}
+
+// run draws the viewer until a quit key is read or keys are exhausted. size
+// is asked for the screen size before every frame, zero keeps the default.
+func (v *viewer) run(keys io.Reader, out io.Writer, size func() (int, int)) error {
+	if _, err := io.WriteString(out, escEnterView); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+	defer func() { _, _ = io.WriteString(out, escLeaveView) }()
+
+	buf := make([]byte, viewReadBufferSize)
+	for {
+		if columns, rows := size(); columns > 0 && rows > 0 {
+			v.width, v.height = columns, rows
+			v.scrollTo(v.top)
+		}
+		if _, err := io.WriteString(out, v.frame()); err != nil {
+			return fmt.Errorf(errorWrapFormat, err)
+		}
+
+		n, err := keys.Read(buf)
+		for _, key := range parseViewKeys(buf[:n]) {
+			if v.handleKey(key) {
+				return nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf(errorWrapFormat, err)
+		}
+	}
+}
+
+// renderView opens the -view pager on the terminal. Keys are read from the
+// controlling terminal, the input may come from a pipe.
+func (t *Tablo) renderView(dataset jsonDataset) error {
+	headers := dataset.headers
+	if dataset.hasHeader {
+		var err error
+		if headers, err = t.renameHeaders(headers); err != nil {
+			return err
+		}
+	}
+
+	tty, restore, err := openViewTerminal()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	return newViewer(headers, dataset.rows).run(tty, t.Output, func() (int, int) {
+		return terminalColumns(tty), terminalRows(tty)
+	})
+}
@@ -0,0 +1,135 @@
+package tablo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testViewer() *viewer {
+	v := newViewer([]string{"NAME", "SIZE", "TAG"}, [][]string{
+		{"web", "10", "latest"},
+		{"db", "3", "15"},
+		{"api", "22", "v1"},
+		{"cache", "7", "latest"},
+	})
+	v.width, v.height = 40, 5
+
+	return v
+}
+
+func TestParseViewKeys(t *testing.T) {
+	keys := parseViewKeys([]byte("j\x1b[A\x1b[6~/ä\r\x7f\x03\x1bq"))
+
+	assert.Equal(t, []string{
+		"j", keyUp, keyPageDown, "/", "ä", keyEnter, keyBackspace, keyInterrupt, keyEscape, "q",
+	}, keys)
+}
+
+func TestNewViewer(t *testing.T) {
+	v := newViewer(nil, [][]string{{"a", strings.Repeat("x", 50)}, {"b"}})
+
+	assert.Equal(t, []string{"1", "2"}, v.headers)
+	assert.Equal(t, []int{1, viewMaxCellWidth}, v.widths)
+	assert.Equal(t, []int{0, 1}, v.order)
+}
+
+func TestViewer_Scroll(t *testing.T) {
+	v := testViewer()
+
+	assert.False(t, v.handleKey("j"))
+	assert.Equal(t, 1, v.top)
+
+	v.handleKey(keyEnd)
+	assert.Equal(t, 2, v.top)
+
+	v.handleKey(keyDown)
+	assert.Equal(t, 2, v.top)
+
+	v.handleKey(keyPageUp)
+	assert.Equal(t, 0, v.top)
+
+	assert.True(t, v.handleKey("q"))
+}
+
+func TestViewer_SortHideShow(t *testing.T) {
+	v := testViewer()
+
+	v.handleKey("l")
+	v.handleKey("s")
+	assert.Equal(t, []int{1, 3, 0, 2}, v.order)
+
+	v.handleKey("s")
+	assert.Equal(t, []int{2, 0, 3, 1}, v.order)
+	assert.Contains(t, v.status(), "sort SIZE desc")
+
+	v.handleKey("x")
+	assert.Equal(t, []bool{false, true, false}, v.hidden)
+	assert.Equal(t, 2, v.column)
+
+	v.handleKey("x")
+	v.handleKey("x")
+	assert.Equal(t, []bool{false, true, true}, v.hidden)
+	assert.Equal(t, 0, v.column)
+
+	v.handleKey("X")
+	assert.Equal(t, []bool{false, false, false}, v.hidden)
+}
+
+func TestViewer_Search(t *testing.T) {
+	v := testViewer()
+	v.height = 4
+
+	for _, key := range []string{"/", "l", "a", "t"} {
+		v.handleKey(key)
+	}
+	assert.Equal(t, "/lat", v.status())
+	assert.Equal(t, 0, v.match)
+
+	v.handleKey(keyEnter)
+	v.handleKey("n")
+	assert.Equal(t, 3, v.match)
+	assert.Equal(t, 3, v.top)
+
+	v.handleKey("n")
+	assert.Equal(t, 0, v.match)
+
+	v.handleKey("N")
+	assert.Equal(t, 3, v.match)
+
+	v.handleKey("/")
+	v.handleKey(keyEscape)
+	assert.Empty(t, v.search)
+	assert.False(t, v.searching)
+}
+
+func TestViewer_Frame(t *testing.T) {
+	v := testViewer()
+	v.width = 12
+	v.search = "web"
+
+	frame := v.frame()
+
+	assert.Contains(t, frame, escBold+escReverse+"NAME "+escReset+" │ "+escBold+"SIZE"+escReset+escClearLine)
+	assert.Contains(t, frame, escReverse+"web  "+escReset+" │ 10  "+escClearLine)
+	assert.Contains(t, frame, strings.Repeat("─", 12))
+	assert.Contains(t, frame, "db    │ 3   "+escClearLine)
+	assert.NotContains(t, frame, "api")
+	assert.Contains(t, frame, escReverse+"rows 1-2 of")
+}
+
+func TestViewer_Run(t *testing.T) {
+	v := testViewer()
+	var out bytes.Buffer
+
+	err := v.run(strings.NewReader("jj"), &out, func() (int, int) { return 30, 6 })
+
+	require.NoError(t, err)
+	assert.Equal(t, 30, v.width)
+	assert.Equal(t, 1, v.top)
+	assert.True(t, strings.HasPrefix(out.String(), escEnterView))
+	assert.True(t, strings.HasSuffix(out.String(), escLeaveView))
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package tablo
+
+import (
+	"fmt"
+	"os"
+)
+
+func openViewTerminal() (*os.File, func(), error) {
+	return nil, nil, fmt.Errorf("%w, view mode is not supported on this platform", ErrInvalidValue)
+}
@@ -0,0 +1,49 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package tablo
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+const ttyPath = "/dev/tty"
+
+// openViewTerminal opens the controlling terminal in raw mode, restore
+// brings back the previous mode and closes it.
+func openViewTerminal() (*os.File, func(), error) {
+	tty, err := os.OpenFile(ttyPath, os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf(errorWrapFormat, err)
+	}
+
+	fd := int(tty.Fd())
+	termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		_ = tty.Close()
+
+		return nil, nil, fmt.Errorf(errorWrapFormat, err)
+	}
+
+	raw := *termios
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err = unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		_ = tty.Close()
+
+		return nil, nil, fmt.Errorf(errorWrapFormat, err)
+	}
+
+	restore := func() {
+		_ = unix.IoctlSetTermios(fd, ioctlSetTermios, termios)
+		_ = tty.Close()
+	}
+
+	return tty, restore, nil
+}
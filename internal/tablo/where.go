@@ -0,0 +1,449 @@
+package tablo
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	whereOpContains = "contains"
+	whereOpMatch    = "~"
+	whereOpNotMatch = "!~"
+)
+
+type whereTokenKind int
+
+const (
+	whereTokenEOF whereTokenKind = iota
+	whereTokenWord
+	whereTokenString
+	whereTokenOperator
+	whereTokenLParen
+	whereTokenRParen
+)
+
+type whereToken struct {
+	kind whereTokenKind
+	text string
+	pos  int
+}
+
+type whereValueKind int
+
+const (
+	whereValueString whereValueKind = iota
+	whereValueNumber
+	whereValueSize
+	whereValueDuration
+)
+
+// whereNode is a node of a parsed -where expression.
+type whereNode interface {
+	eval(fields []string) bool
+}
+
+type whereAnd struct{ left, right whereNode }
+
+type whereOr struct{ left, right whereNode }
+
+type whereNot struct{ node whereNode }
+
+type whereComparison struct {
+	column string
+	// numbered comparisons keep their index, named ones are bound to the
+	// headers of every input.
+	numbered bool
+	index    int
+	op       string
+	value    string
+	kind     whereValueKind
+	number   float64
+	re       *regexp.Regexp
+}
+
+func (n whereAnd) eval(fields []string) bool { return n.left.eval(fields) && n.right.eval(fields) }
+
+func (n whereOr) eval(fields []string) bool { return n.left.eval(fields) || n.right.eval(fields) }
+
+func (n whereNot) eval(fields []string) bool { return !n.node.eval(fields) }
+
+func (c *whereComparison) eval(fields []string) bool {
+	cell := fieldAt(fields, c.index)
+
+	switch c.op {
+	case whereOpContains:
+		return strings.Contains(cell, c.value)
+	case whereOpMatch:
+		return c.re.MatchString(cell)
+	case whereOpNotMatch:
+		return !c.re.MatchString(cell)
+	}
+
+	if c.kind == whereValueString {
+		return compareWithOperator(strings.Compare(cell, c.value), c.op)
+	}
+
+	var (
+		n  float64
+		ok bool
+	)
+	switch c.kind {
+	case whereValueSize:
+		n, ok = parseHumanSize(cell)
+	case whereValueDuration:
+		var d time.Duration
+		d, ok = parseHumanDuration(cell)
+		n = d.Seconds()
+	default:
+		n, ok = parseNumber(cell)
+	}
+	if !ok {
+		return c.op == "!="
+	}
+
+	switch {
+	case n < c.number:
+		return compareWithOperator(-1, c.op)
+	case n > c.number:
+		return compareWithOperator(1, c.op)
+	default:
+		return compareWithOperator(0, c.op)
+	}
+}
+
+func compareWithOperator(c int, op string) bool {
+	switch op {
+	case "=", "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	default:
+		return false
+	}
+}
+
+type whereParser struct {
+	expr   string
+	tokens []whereToken
+	pos    int
+}
+
+func whereError(expr string, pos int, format string, args ...any) error {
+	return fmt.Errorf("%w, where %q: %s at position %d", ErrInvalidValue, expr, fmt.Sprintf(format, args...), pos+1)
+}
+
+func isWhereOperatorRune(r rune) bool {
+	return strings.ContainsRune("=!<>~", r)
+}
+
+func isWhereWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !isWhereOperatorRune(r) && !strings.ContainsRune(`()"'`, r)
+}
+
+func tokenizeWhere(expr string) ([]whereToken, error) {
+	var tokens []whereToken
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, whereToken{kind: whereTokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, whereToken{kind: whereTokenRParen, text: ")", pos: i})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == r || runes[i+1] == '\\') {
+					i++
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, whereError(expr, start, "unterminated string")
+			}
+			i++
+			tokens = append(tokens, whereToken{kind: whereTokenString, text: b.String(), pos: start})
+		case isWhereOperatorRune(r):
+			start := i
+			for i < len(runes) && isWhereOperatorRune(runes[i]) {
+				i++
+			}
+			op := string(runes[start:i])
+			if op == "!" {
+				tokens = append(tokens, whereToken{kind: whereTokenWord, text: "not", pos: start})
+				continue
+			}
+			if !slices.Contains([]string{"=", "==", "!=", "<", "<=", ">", ">=", "~", "!~"}, op) {
+				return nil, whereError(expr, start, "unknown operator %q", op)
+			}
+			tokens = append(tokens, whereToken{kind: whereTokenOperator, text: op, pos: start})
+		default:
+			start := i
+			for i < len(runes) && isWhereWordRune(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			switch word {
+			case "&&":
+				word = "and"
+			case "||":
+				word = "or"
+			}
+			tokens = append(tokens, whereToken{kind: whereTokenWord, text: word, pos: start})
+		}
+	}
+
+	return append(tokens, whereToken{kind: whereTokenEOF, pos: len(runes)}), nil
+}
+
+// parseWhere parses a row filter expression such as
+// `STATUS ~ "^Up" and not (SIZE > 10mb or 2 contains tmp)`.
+func parseWhere(expr string) (whereNode, error) {
+	tokens, err := tokenizeWhere(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &whereParser{expr: expr, tokens: tokens}
+	if p.peek().kind == whereTokenEOF {
+		return nil, whereError(expr, 0, "empty expression")
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != whereTokenEOF {
+		return nil, whereError(expr, tok.pos, "unexpected %q", tok.text)
+	}
+
+	return node, nil
+}
+
+func (p *whereParser) peek() whereToken {
+	return p.tokens[p.pos]
+}
+
+func (p *whereParser) next() whereToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != whereTokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *whereParser) acceptKeyword(keyword string) bool {
+	tok := p.peek()
+	if tok.kind == whereTokenWord && strings.EqualFold(tok.text, keyword) {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *whereParser) parseOr() (whereNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptKeyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = whereOr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *whereParser) parseAnd() (whereNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptKeyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = whereAnd{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *whereParser) parseUnary() (whereNode, error) {
+	if p.acceptKeyword("not") {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return whereNot{node: node}, nil
+	}
+
+	if p.peek().kind == whereTokenLParen {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != whereTokenRParen {
+			return nil, whereError(p.expr, tok.pos, "expected \")\"")
+		}
+
+		return node, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *whereParser) parseComparison() (whereNode, error) {
+	columnToken := p.next()
+	if columnToken.kind != whereTokenWord && columnToken.kind != whereTokenString {
+		return nil, whereError(p.expr, columnToken.pos, "expected column")
+	}
+
+	comparison := &whereComparison{column: columnToken.text, index: -1}
+	if n, err := strconv.Atoi(columnToken.text); err == nil && columnToken.kind == whereTokenWord {
+		if n < 1 {
+			return nil, whereError(p.expr, columnToken.pos, "column index must be greater than zero")
+		}
+		comparison.numbered, comparison.index = true, n-1
+	}
+
+	opToken := p.next()
+	switch {
+	case opToken.kind == whereTokenOperator:
+		comparison.op = opToken.text
+	case opToken.kind == whereTokenWord && strings.EqualFold(opToken.text, whereOpContains):
+		comparison.op = whereOpContains
+	default:
+		return nil, whereError(p.expr, opToken.pos, "expected operator after %q", columnToken.text)
+	}
+
+	valueToken := p.next()
+	if valueToken.kind != whereTokenWord && valueToken.kind != whereTokenString {
+		return nil, whereError(p.expr, valueToken.pos, "expected value after %q", comparison.op)
+	}
+	comparison.value = valueToken.text
+
+	if comparison.op == whereOpMatch || comparison.op == whereOpNotMatch {
+		re, err := regexp.Compile(valueToken.text)
+		if err != nil {
+			return nil, whereError(p.expr, valueToken.pos, "invalid regex: %v", err)
+		}
+		comparison.re = re
+
+		return comparison, nil
+	}
+
+	if valueToken.kind == whereTokenWord {
+		comparison.classifyValue()
+	}
+
+	return comparison, nil
+}
+
+// classifyValue picks the comparison type from an unquoted literal; quoted
+// literals always compare as strings.
+func (c *whereComparison) classifyValue() {
+	if n, ok := parseNumber(c.value); ok {
+		c.kind, c.number = whereValueNumber, n
+		return
+	}
+	if isSizeLiteral(c.value) {
+		n, _ := parseHumanSize(c.value)
+		c.kind, c.number = whereValueSize, n
+		return
+	}
+	if d, ok := parseHumanDuration(c.value); ok {
+		c.kind, c.number = whereValueDuration, d.Seconds()
+	}
+}
+
+// resolveWhere binds header names to column indices. It runs for every
+// input, so a reused expression follows the header order of the input.
+func resolveWhere(node whereNode, headers []string) error {
+	switch n := node.(type) {
+	case whereAnd:
+		if err := resolveWhere(n.left, headers); err != nil {
+			return err
+		}
+		return resolveWhere(n.right, headers)
+	case whereOr:
+		if err := resolveWhere(n.left, headers); err != nil {
+			return err
+		}
+		return resolveWhere(n.right, headers)
+	case whereNot:
+		return resolveWhere(n.node, headers)
+	case *whereComparison:
+		if n.numbered {
+			return nil
+		}
+		idx := slices.IndexFunc(headers, func(header string) bool {
+			return strings.EqualFold(header, n.column)
+		})
+		if idx < 0 {
+			return fmt.Errorf("%w, where column %q not found", ErrInvalidValue, n.column)
+		}
+		n.index = idx
+	}
+
+	return nil
+}
+
+// filterRecords keeps the data records that match the -where expression. A
+// header row (detected or implied by column selection) is always kept.
+func (t *Tablo) filterRecords(records [][]string) ([][]string, error) {
+	if t.Where == nil || len(records) == 0 {
+		return records, nil
+	}
+
+	headers := t.leadingHeaders(records)
+	if err := resolveWhere(t.Where, headers); err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if headers != nil {
+		start = 1
+	}
+
+	filtered := make([][]string, 0, len(records))
+	filtered = append(filtered, records[:start]...)
+	for _, fields := range records[start:] {
+		if t.Where.eval(fields) {
+			filtered = append(filtered, fields)
+		}
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,137 @@
+package tablo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWhere_Eval(t *testing.T) {
+	headers := []string{"NAME", "SIZE", "CREATED", "STATUS", "COUNT"}
+	row := []string{"web-api", "12.7MB", "22 hours ago", "Up 3 days", "42"}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"NAME = web-api", true},
+		{"name == 'web-api'", true},
+		{"NAME != web-api", false},
+		{"NAME ~ ^web", true},
+		{"NAME !~ ^web", false},
+		{"STATUS contains Up", true},
+		{"STATUS CONTAINS down", false},
+		{"SIZE > 10mb", true},
+		{"SIZE < 1GB", true},
+		{"SIZE >= 13MB", false},
+		{"CREATED < 1d", true},
+		{"CREATED > 2h", true},
+		{"COUNT > 40 and COUNT <= 42", true},
+		{"COUNT = 42.0", true},
+		{"COUNT = '42.0'", false},
+		{"5 = 42", true},
+		{"not NAME = web-api", false},
+		{"!(NAME = db) && SIZE > 1kb", true},
+		{"NAME = db or (COUNT > 1 and not STATUS ~ Exited)", true},
+		{"NAME = db || COUNT < 1", false},
+		{"NAME > 5", false},
+		{"NAME != 5", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			node, err := parseWhere(tt.expr)
+			require.NoError(t, err)
+			require.NoError(t, resolveWhere(node, headers))
+
+			assert.Equal(t, tt.want, node.eval(row))
+		})
+	}
+}
+
+func TestParseWhere_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		msg  string
+	}{
+		{"", "empty expression"},
+		{"NAME", "expected operator"},
+		{"NAME =", "expected value"},
+		{"NAME = 'web", "unterminated string"},
+		{"NAME =< 1", "unknown operator"},
+		{"(NAME = a", `expected ")"`},
+		{"NAME = a b", `unexpected "b"`},
+		{"NAME ~ (", "expected value"},
+		{"NAME ~ '['", "invalid regex"},
+		{"0 = 1", "greater than zero"},
+		{"and", "expected operator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := parseWhere(tt.expr)
+
+			assert.ErrorIs(t, err, ErrInvalidValue)
+			assert.ErrorContains(t, err, tt.msg)
+		})
+	}
+}
+
+func TestResolveWhere_UnknownColumn(t *testing.T) {
+	node, err := parseWhere("NAME = a or MISSING = b")
+	require.NoError(t, err)
+
+	err = resolveWhere(node, []string{"NAME"})
+
+	assert.ErrorIs(t, err, ErrInvalidValue)
+	assert.ErrorContains(t, err, `"MISSING"`)
+}
+
+func TestTablo_FilterRecords(t *testing.T) {
+	node, err := parseWhere("age >= 18")
+	require.NoError(t, err)
+
+	tbl := &Tablo{
+		FieldDelimiter: '|',
+		Where:          node,
+	}
+
+	records, err := tbl.filterRecords(tbl.splitRecords([]string{"name|age", "vigo|42", "kid|7", "unknown|"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"name", "age"}, {"vigo", "42"}}, records)
+}
+
+func TestTablo_FilterRecords_WithoutHeaderUsesIndexes(t *testing.T) {
+	node, err := parseWhere("3 = /bin/sh")
+	require.NoError(t, err)
+
+	tbl := &Tablo{
+		FieldDelimiter: ':',
+		Where:          node,
+	}
+
+	records, err := tbl.filterRecords(tbl.splitRecords([]string{"root:0:/bin/sh", "nobody:-2:/usr/bin/false"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"root", "0", "/bin/sh"}}, records)
+}
+
+func TestTablo_FilterRecords_ResolvesEveryInput(t *testing.T) {
+	node, err := parseWhere("age >= 18")
+	require.NoError(t, err)
+
+	tbl := &Tablo{
+		FieldDelimiter: '|',
+		Where:          node,
+	}
+
+	records, err := tbl.filterRecords(tbl.splitRecords([]string{"name|age", "vigo|42", "kid|7"}))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"name", "age"}, {"vigo", "42"}}, records)
+
+	records, err = tbl.filterRecords(tbl.splitRecords([]string{"age|name", "42|vigo", "7|kid"}))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"age", "name"}, {"42", "vigo"}}, records)
+}
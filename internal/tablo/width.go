@@ -0,0 +1,276 @@
+package tablo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// WidthMode defines how cells wider than their column are shortened.
+type WidthMode string
+
+// width modes.
+const (
+	WidthWrap     WidthMode = "wrap"
+	WidthTruncate WidthMode = "truncate"
+	WidthEllipsis WidthMode = "ellipsis"
+)
+
+const (
+	widthSpecSeparator = ":"
+	widthAllColumns    = "*"
+	minColumnWidth     = 3
+
+	// maxWidthUnlimited turns off fitting the table to the terminal.
+	maxWidthUnlimited = -1
+)
+
+var widthModes = []WidthMode{WidthWrap, WidthTruncate, WidthEllipsis}
+
+// TerminalWidth returns the number of columns of the terminal w writes to,
+// zero when w is not a terminal.
+var TerminalWidth = func(w io.Writer) int {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0
+	}
+
+	return terminalColumns(f)
+}
+
+// columnWidth limits the width of a column given by header name or, when
+// index is not negative, by zero-based output column index. The * column
+// applies to every column.
+type columnWidth struct {
+	column string
+	index  int
+	width  int
+}
+
+// parseMaxWidth parses a comma separated list of WIDTH and COLUMN:WIDTH
+// entries. A bare WIDTH limits the whole table, 0 keeps the table from
+// being fitted to the terminal.
+func parseMaxWidth(spec string) (int, []columnWidth, error) {
+	var (
+		tableWidth int
+		columns    []columnWidth
+	)
+
+	for item := range strings.SplitSeq(spec, ",") {
+		item = strings.TrimSpace(item)
+		idx := strings.LastIndex(item, widthSpecSeparator)
+
+		width, err := strconv.Atoi(item[idx+1:])
+		if err != nil || width < 0 {
+			return 0, nil, fmt.Errorf("%w, max width entry %q must be WIDTH or COLUMN:WIDTH", ErrInvalidValue, item)
+		}
+
+		if idx < 0 {
+			tableWidth = width
+			if width == 0 {
+				tableWidth = maxWidthUnlimited
+			}
+
+			continue
+		}
+
+		column := item[:idx]
+		if column == "" || width < 1 {
+			return 0, nil, fmt.Errorf("%w, max width entry %q must be COLUMN:WIDTH", ErrInvalidValue, item)
+		}
+
+		entry := columnWidth{column: column, index: -1, width: width}
+		if n, errIndex := strconv.Atoi(column); errIndex == nil {
+			if n < 1 {
+				return 0, nil, fmt.Errorf("%w, max width column index %d must be greater than zero", ErrInvalidValue, n)
+			}
+			entry.index = n - 1
+		}
+		columns = append(columns, entry)
+	}
+
+	return tableWidth, columns, nil
+}
+
+func parseWidthMode(s string) (WidthMode, error) {
+	mode := WidthMode(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(widthModes, mode) {
+		return "", fmt.Errorf("%w, unknown width mode %q", ErrInvalidValue, s)
+	}
+
+	return mode, nil
+}
+
+func widthModeNames() []string {
+	names := make([]string, 0, len(widthModes))
+	for _, mode := range widthModes {
+		names = append(names, string(mode))
+	}
+
+	return names
+}
+
+// widthEnforcer returns the go-pretty transformer that shortens a cell to
+// its maximum width.
+func (t *Tablo) widthEnforcer() table.WidthEnforcer {
+	switch t.WidthMode {
+	case WidthTruncate:
+		return eachLine(text.Trim)
+	case WidthEllipsis:
+		return eachLine(func(value string, width int) string {
+			return text.Snip(value, width, streamSnipIndicator)
+		})
+	default:
+		return text.WrapSoft
+	}
+}
+
+// eachLine applies a width enforcer to every line of a multi-line cell.
+func eachLine(enforce table.WidthEnforcer) table.WidthEnforcer {
+	return func(value string, width int) string {
+		lines := strings.Split(value, "\n")
+		for i, line := range lines {
+			lines[i] = enforce(line, width)
+		}
+
+		return strings.Join(lines, "\n")
+	}
+}
+
+// naturalColumnWidths returns the widest line of every column.
+func naturalColumnWidths(headers []string, rows [][]string) []int {
+	widths := make([]int, len(headers))
+	measure := func(fields []string) {
+		for i, field := range fields {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], text.LongestLineLen(field))
+		}
+	}
+
+	measure(headers)
+	for _, row := range rows {
+		measure(row)
+	}
+
+	return widths
+}
+
+// tableOverhead returns the width taken by borders, column separators and
+// cell padding.
+func tableOverhead(style table.Style, columns int) int {
+	if columns == 0 {
+		return 0
+	}
+
+	padding := text.StringWidth(style.Box.PaddingLeft) + text.StringWidth(style.Box.PaddingRight)
+	overhead := columns * padding
+	if style.Options.SeparateColumns {
+		overhead += (columns - 1) * text.StringWidth(style.Box.MiddleVertical)
+	}
+	if style.Options.DrawBorder {
+		overhead += text.StringWidth(style.Box.Left) + text.StringWidth(style.Box.Right)
+	}
+
+	return overhead
+}
+
+// shrinkWidths narrows the widest column one step at a time until the
+// widths fit into available, no column goes below minColumnWidth.
+func shrinkWidths(widths []int, available int) {
+	var total int
+	for _, width := range widths {
+		total += width
+	}
+
+	for total > available {
+		widest := 0
+		for i, width := range widths {
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// tableWidth returns the width the table has to fit in: -max-width when
+// given, otherwise the terminal width, zero for no limit.
+func (t *Tablo) tableWidth() int {
+	switch {
+	case t.MaxWidth == maxWidthUnlimited:
+		return 0
+	case t.MaxWidth > 0:
+		return t.MaxWidth
+	default:
+		return TerminalWidth(t.Output)
+	}
+}
+
+// maxColumnWidths caps the natural column widths with the -max-width
+// columns, then shrinks the widest columns first until the table fits.
+func (t *Tablo) maxColumnWidths(headers []string, natural []int, style table.Style) ([]int, error) {
+	widths := slices.Clone(natural)
+
+	for _, entry := range t.ColumnWidths {
+		if entry.column == widthAllColumns {
+			for i := range widths {
+				widths[i] = min(widths[i], entry.width)
+			}
+
+			continue
+		}
+
+		idx := entry.index
+		if idx < 0 {
+			idx = slices.IndexFunc(headers, func(header string) bool {
+				return strings.EqualFold(header, entry.column)
+			})
+		}
+		if idx < 0 || idx >= len(widths) {
+			return nil, fmt.Errorf("%w, max width column %q not found", ErrInvalidValue, entry.column)
+		}
+		widths[idx] = min(widths[idx], entry.width)
+	}
+
+	if limit := t.tableWidth(); limit > 0 {
+		shrinkWidths(widths, limit-tableOverhead(style, len(widths)))
+	}
+
+	return widths, nil
+}
+
+// widthLimits returns the WidthMax of every rendered column, zero for the
+// columns that keep their natural width.
+func (t *Tablo) widthLimits(headers []string, rows [][]string, style table.Style) ([]int, error) {
+	measured := headers
+	if t.HideHeaders {
+		measured = nil
+	}
+
+	natural := naturalColumnWidths(measured, rows)
+	widths, err := t.maxColumnWidths(headers, natural, style)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make([]int, len(widths))
+	for i, width := range widths {
+		if width < natural[i] {
+			limits[i] = width
+		}
+	}
+
+	return limits, nil
+}
@@ -0,0 +1,101 @@
+package tablo
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaxWidth(t *testing.T) {
+	tableWidth, columns, err := parseMaxWidth("100, COMMAND:30,2:10,*:40")
+
+	require.NoError(t, err)
+	assert.Equal(t, 100, tableWidth)
+	assert.Equal(t, []columnWidth{
+		{column: "COMMAND", index: -1, width: 30},
+		{column: "2", index: 1, width: 10},
+		{column: "*", index: -1, width: 40},
+	}, columns)
+
+	tableWidth, _, err = parseMaxWidth("0")
+
+	require.NoError(t, err)
+	assert.Equal(t, maxWidthUnlimited, tableWidth)
+
+	for _, spec := range []string{"wide", "-1", ":10", "NAME:0", "0:10", "NAME:x", "80,"} {
+		_, _, err = parseMaxWidth(spec)
+		assert.ErrorIs(t, err, ErrInvalidValue, spec)
+	}
+}
+
+func TestParseWidthMode(t *testing.T) {
+	mode, err := parseWidthMode(" Ellipsis ")
+
+	require.NoError(t, err)
+	assert.Equal(t, WidthEllipsis, mode)
+
+	_, err = parseWidthMode("clip")
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestTablo_WidthEnforcer(t *testing.T) {
+	value := "the quick brown fox\njumps"
+
+	assert.Equal(t, "the quick\nbrown fox\njumps", (&Tablo{}).widthEnforcer()(value, 9))
+	assert.Equal(t, "the quick\njumps", (&Tablo{WidthMode: WidthTruncate}).widthEnforcer()(value, 9))
+	assert.Equal(t, "the quic…\njumps", (&Tablo{WidthMode: WidthEllipsis}).widthEnforcer()(value, 9))
+}
+
+func TestShrinkWidths(t *testing.T) {
+	widths := []int{6, 40, 20}
+	shrinkWidths(widths, 40)
+
+	assert.Equal(t, []int{6, 17, 17}, widths)
+
+	widths = []int{4, 5}
+	shrinkWidths(widths, 2)
+
+	assert.Equal(t, []int{3, 3}, widths)
+}
+
+func TestTableOverhead(t *testing.T) {
+	style := defaultStyle()
+
+	assert.Equal(t, 10, tableOverhead(*style, 3))
+
+	style.Options.DrawBorder = false
+
+	assert.Equal(t, 8, tableOverhead(*style, 3))
+	assert.Equal(t, 0, tableOverhead(*style, 0))
+}
+
+func TestTablo_MaxColumnWidths(t *testing.T) {
+	oldTerminalWidth := TerminalWidth
+	defer func() { TerminalWidth = oldTerminalWidth }()
+	TerminalWidth = func(io.Writer) int { return 30 }
+
+	headers := []string{"HASH", "MESSAGE", "AUTHOR"}
+	natural := []int{6, 40, 6}
+
+	widths, err := (&Tablo{}).maxColumnWidths(headers, natural, *defaultStyle())
+	require.NoError(t, err)
+	assert.Equal(t, []int{6, 8, 6}, widths)
+
+	widths, err = (&Tablo{MaxWidth: maxWidthUnlimited}).maxColumnWidths(headers, natural, *defaultStyle())
+	require.NoError(t, err)
+	assert.Equal(t, natural, widths)
+
+	tbl := &Tablo{
+		MaxWidth:     maxWidthUnlimited,
+		ColumnWidths: []columnWidth{{column: "message", index: -1, width: 12}, {column: "*", index: -1, width: 5}},
+	}
+	widths, err = tbl.maxColumnWidths(headers, natural, *defaultStyle())
+	require.NoError(t, err)
+	assert.Equal(t, []int{5, 5, 5}, widths)
+
+	tbl.ColumnWidths = []columnWidth{{column: "size", index: -1, width: 5}}
+	_, err = tbl.maxColumnWidths(headers, natural, *defaultStyle())
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
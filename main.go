@@ -4,6 +4,8 @@ Package main implements cli execution.
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 
@@ -12,6 +14,12 @@ import (
 
 func main() {
 	if err := tablo.Run(); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if errors.Is(err, tablo.ErrInvalidFlag) {
+			os.Exit(2)
+		}
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,186 @@
+/*
+Package tablo parses delimited, fixed-width, JSON and YAML text into a
+Dataset and renders datasets as tables, JSON, CSV, TSV, Markdown, HTML or
+LaTeX, the same way the tablo command does.
+
+	dataset, err := tablo.Parse(os.Stdin, tablo.ParseOptions{Sort: "SIZE:desc"})
+	if err != nil {
+		return err
+	}
+	return tablo.Render(dataset, os.Stdout, tablo.RenderOptions{Format: "markdown"})
+*/
+package tablo
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	core "github.com/vigo/tablo/internal/tablo"
+)
+
+const (
+	defaultLineDelimiter = "\n"
+	errorWrapFormat      = "%w"
+)
+
+// Dataset is parsed input: the header row, the data rows, the inferred
+// column types, the field delimiter and the input format. Headers is nil
+// when the input has no header row.
+type Dataset = core.Dataset
+
+// ColumnType is the type inferred from every non-empty value of a column.
+type ColumnType = core.ColumnType
+
+// InputFormat is the format input was read as.
+type InputFormat = core.InputFormat
+
+// column types.
+const (
+	ColumnString = core.ColumnString
+	ColumnInt    = core.ColumnInt
+	ColumnFloat  = core.ColumnFloat
+	ColumnBool   = core.ColumnBool
+)
+
+// sentinel errors.
+var (
+	ErrValueRequired = core.ErrValueRequired
+	ErrInvalidValue  = core.ErrInvalidValue
+	ErrInvalidFile   = core.ErrInvalidFile
+	ErrInvalidFlag   = core.ErrInvalidFlag
+)
+
+// ParseOptions sets how input is read and which rows and columns are kept.
+// Values use the syntax of the matching tablo flag, empty values keep the
+// defaults.
+type ParseOptions struct {
+	FieldDelimiter string   // -f, empty detects , ; tab and | or splits at 2+ spaces
+	FieldPattern   string   // -f-regex
+	LineDelimiter  string   // -l, default "\n" also matches "\r\n" and "\r"
+	InputFormat    string   // -if, default auto
+	Widths         string   // -fw
+	RawSplit       bool     // -rs
+	Columns        []string // COLUMN arguments
+	FilterIndexes  string   // -fi
+	Where          string   // -w
+	Sort           string   // -s
+	GroupBy        string   // -gb
+	Aggregates     string   // -ag
+	AddColumns     string   // -ac
+}
+
+func (o ParseOptions) options() []core.Option {
+	lineDelimiter := o.LineDelimiter
+	if lineDelimiter == "" {
+		lineDelimiter = defaultLineDelimiter
+	}
+
+	return []core.Option{
+		core.WithFieldDelimiter(o.FieldDelimiter),
+		core.WithFieldDelimiterRegex(o.FieldPattern),
+		core.WithLineDelimiter(lineDelimiter),
+		core.WithInputFormat(o.InputFormat),
+		core.WithFixedWidths(o.Widths),
+		core.WithRawSplit(o.RawSplit),
+		core.WithArgs(o.Columns),
+		core.WithFilterIndexes(o.FilterIndexes),
+		core.WithWhere(o.Where),
+		core.WithSort(o.Sort),
+		core.WithGroupBy(o.GroupBy),
+		core.WithAggregates(o.Aggregates),
+		core.WithAddColumns(o.AddColumns),
+	}
+}
+
+// RenderOptions sets the output format and how it looks. Values use the
+// syntax of the matching tablo flag, empty values keep the defaults.
+type RenderOptions struct {
+	Format         string // -fmt, default table
+	Style          string // -sy, default light
+	Theme          string // -th
+	Color          string // -c, default auto
+	NoBorders      bool   // -nb
+	NoSeparateRows bool   // -n
+	NoHeaders      bool   // -nh
+	Align          string // -a
+	MaxWidth       string // -mw
+	WidthMode      string // -wm
+	Footer         string // -ft
+	Rename         string // -rn
+	JSONTypes      string // -jt
+	JSONSchema     string // -js
+}
+
+func (o RenderOptions) options() []core.Option {
+	return []core.Option{
+		core.WithFormat(o.Format),
+		core.WithStyle(o.Style),
+		core.WithTheme(o.Theme),
+		core.WithColor(o.Color),
+		core.WithNoDrawBorder(o.NoBorders),
+		core.WithNoSeparateRows(o.NoSeparateRows),
+		core.WithNoHeaders(o.NoHeaders),
+		core.WithAlign(o.Align),
+		core.WithMaxWidth(o.MaxWidth),
+		core.WithWidthMode(o.WidthMode),
+		core.WithFooter(o.Footer),
+		core.WithRenames(o.Rename),
+		core.WithJSONTypes(o.JSONTypes),
+		core.WithJSONSchema(o.JSONSchema),
+	}
+}
+
+// nopCloser keeps the caller's writer open, rendering never closes it.
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// Parse reads r and returns its headers, rows and what was detected.
+func Parse(r io.Reader, options ParseOptions) (*Dataset, error) {
+	t, err := core.New(options.options()...)
+	if err != nil {
+		return nil, fmt.Errorf(errorWrapFormat, err)
+	}
+
+	dataset, err := t.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return dataset, nil
+}
+
+// Render writes dataset to w. Datasets may come from Parse or be built by
+// hand.
+func Render(dataset *Dataset, w io.Writer, options RenderOptions) error {
+	if dataset == nil {
+		return fmt.Errorf("%w, dataset is nil", ErrValueRequired)
+	}
+
+	t, err := core.New(append(options.options(), core.WithOutputWriter(nopCloser{w}))...)
+	if err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
+	if err = t.Render(dataset); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return nil
+}
+
+// RunWith runs the tablo command with args, program name first, reading
+// stdin and writing to stdout and stderr. env holds KEY=VALUE pairs and
+// replaces the process environment, so commands can run concurrently.
+func RunWith(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, env []string) error {
+	if err := core.RunWith(ctx, args, stdin, stdout, stderr, env); err != nil {
+		return fmt.Errorf(errorWrapFormat, err)
+	}
+
+	return nil
+}
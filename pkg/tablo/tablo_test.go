@@ -0,0 +1,162 @@
+package tablo_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vigo/tablo/pkg/tablo"
+)
+
+func TestParse(t *testing.T) {
+	input := "NAME;SIZE;ACTIVE\nweb;12.5;true\ndb;3;false\napi;22;true\n"
+
+	dataset, err := tablo.Parse(strings.NewReader(input), tablo.ParseOptions{
+		Where: "SIZE > 5",
+		Sort:  "SIZE:desc",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"NAME", "SIZE", "ACTIVE"}, dataset.Headers)
+	assert.Equal(t, [][]string{{"api", "22", "true"}, {"web", "12.5", "true"}}, dataset.Rows)
+	assert.Equal(t, []tablo.ColumnType{tablo.ColumnString, tablo.ColumnFloat, tablo.ColumnBool}, dataset.Types)
+	assert.Equal(t, ";", dataset.FieldDelimiter)
+	assert.Equal(t, tablo.InputFormat("text"), dataset.InputFormat)
+}
+
+func TestParse_SmartSplitWithoutHeader(t *testing.T) {
+	dataset, err := tablo.Parse(strings.NewReader("1  one\n2  two\n"), tablo.ParseOptions{})
+
+	require.NoError(t, err)
+	assert.Nil(t, dataset.Headers)
+	assert.Equal(t, [][]string{{"1", "one"}, {"2", "two"}}, dataset.Rows)
+	assert.Equal(t, []tablo.ColumnType{tablo.ColumnInt, tablo.ColumnString}, dataset.Types)
+	assert.Empty(t, dataset.FieldDelimiter)
+}
+
+func TestParse_FieldPattern(t *testing.T) {
+	dataset, err := tablo.Parse(strings.NewReader("NAME | SIZE\nweb|12\ndb  |  3\n"), tablo.ParseOptions{
+		FieldPattern: `\s*\|\s*`,
+		Columns:      []string{"NAME", "SIZE"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"NAME", "SIZE"}, dataset.Headers)
+	assert.Equal(t, [][]string{{"web", "12"}, {"db", "3"}}, dataset.Rows)
+	assert.Empty(t, dataset.FieldDelimiter)
+	assert.Equal(t, `\s*\|\s*`, dataset.FieldPattern)
+}
+
+func TestParse_FilterIndexesWithHeader(t *testing.T) {
+	dataset, err := tablo.Parse(strings.NewReader("NAME,SIZE\nweb,12\ndb,3\n"), tablo.ParseOptions{
+		FilterIndexes: "2",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SIZE"}, dataset.Headers)
+	assert.Equal(t, [][]string{{"12"}, {"3"}}, dataset.Rows)
+	assert.Equal(t, []tablo.ColumnType{tablo.ColumnInt}, dataset.Types)
+
+	var out bytes.Buffer
+	err = tablo.Render(dataset, &out, tablo.RenderOptions{Format: "csv"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "SIZE\n12\n3\n", out.String())
+}
+
+func TestParse_JSON(t *testing.T) {
+	dataset, err := tablo.Parse(
+		strings.NewReader(`[{"name":"web","port":80},{"name":"db","port":5432}]`),
+		tablo.ParseOptions{Columns: []string{"port"}},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"port"}, dataset.Headers)
+	assert.Equal(t, [][]string{{"80"}, {"5432"}}, dataset.Rows)
+	assert.Equal(t, tablo.InputFormat("json"), dataset.InputFormat)
+}
+
+func TestParse_InvalidOptions(t *testing.T) {
+	_, err := tablo.Parse(strings.NewReader("a,b\n"), tablo.ParseOptions{Sort: "a:sideways"})
+
+	assert.ErrorIs(t, err, tablo.ErrInvalidValue)
+}
+
+func TestRender(t *testing.T) {
+	dataset, err := tablo.Parse(strings.NewReader("NAME,SIZE\nweb,12\ndb,3\n"), tablo.ParseOptions{})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = tablo.Render(dataset, &out, tablo.RenderOptions{Footer: "SIZE:sum", NoSeparateRows: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, `┌──────┬──────┐
+│ NAME │ SIZE │
+│ web  │   12 │
+│ db   │    3 │
+├──────┼──────┤
+│      │   15 │
+└──────┴──────┘
+`, out.String())
+}
+
+func TestRender_BuiltDataset(t *testing.T) {
+	dataset := &tablo.Dataset{
+		Headers: []string{"name", "port"},
+		Rows:    [][]string{{"web", "80"}, {"db, primary", "5432"}},
+	}
+
+	var out bytes.Buffer
+	err := tablo.Render(dataset, &out, tablo.RenderOptions{Format: "json", JSONTypes: "infer", Rename: "port=p"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name":"web","p":80},{"name":"db, primary","p":5432}]`, out.String())
+
+	out.Reset()
+	err = tablo.Render(&tablo.Dataset{Rows: [][]string{{"name", "age"}, {"vigo", "42"}}}, &out, tablo.RenderOptions{
+		Format: "csv",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "name,age\nvigo,42\n", out.String())
+}
+
+func TestRender_FooterKeepsDatasetRows(t *testing.T) {
+	rows := make([][]string, 0, 3)
+	rows = append(rows, []string{"web", "12"}, []string{"db", "3"})
+	dataset := &tablo.Dataset{Headers: []string{"NAME", "SIZE"}, Rows: rows}
+
+	for _, format := range []string{"csv", "table"} {
+		var out bytes.Buffer
+		err := tablo.Render(dataset, &out, tablo.RenderOptions{Format: format, Footer: "SIZE:sum"})
+
+		require.NoError(t, err)
+		assert.Nil(t, rows[:3][2], format)
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	var out bytes.Buffer
+
+	assert.ErrorIs(t, tablo.Render(nil, &out, tablo.RenderOptions{}), tablo.ErrValueRequired)
+	assert.ErrorIs(t, tablo.Render(&tablo.Dataset{}, &out, tablo.RenderOptions{Format: "pdf"}), tablo.ErrInvalidValue)
+}
+
+func TestRunWith(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := tablo.RunWith(
+		context.Background(),
+		[]string{"tablo", "-fmt", "markdown", "name"},
+		strings.NewReader("name,age\nvigo,42\n"),
+		&stdout, &stderr,
+		[]string{"TABLO_CONFIG=/nonexistent"},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, "| name |\n| --- |\n| vigo |\n", stdout.String())
+	assert.Empty(t, stderr.String())
+}